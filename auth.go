@@ -0,0 +1,163 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// OnAuthChange registers fn to be called after every change of the client's auth state:
+// a new or refreshed token with its auth record (decoded as map[string]any, nil if unknown)
+// and an empty token with nil record after ClearAuth or Logout.
+//
+// The hooks are called synchronously by the request that changed the state.
+func (c *Client) OnAuthChange(fn func(token string, record any)) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	c.authHooks = append(c.authHooks, fn)
+}
+
+// AuthRecord returns the raw JSON of the authenticated record, it's nil if unknown.
+func (c *Client) AuthRecord() json.RawMessage {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	return c.record
+}
+
+// ClearAuth removes the token and the auth record from the client and its AuthStore.
+// The clients with credentials (e.g. WithAdminEmailPassword) authenticate again
+// with the next request, use Logout to forget them too. The other clients are anonymous.
+func (c *Client) ClearAuth() error {
+	a := c.auth()
+	a.reset()
+	switch a.(type) {
+	case *authorizeToken, *authorizeStaticToken:
+		c.setAuthorizer(authorizeNoOp{}) // nothing to authenticate with
+	}
+	return c.authChanged(authResponse{})
+}
+
+// Logout clears the auth state and forgets the credentials,
+// the client is anonymous from now on.
+func (c *Client) Logout() error {
+	c.setAuthorizer(authorizeNoOp{})
+	return c.ClearAuth()
+}
+
+func (c *Client) auth() tokenAuthorizer {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	return c.authorizer
+}
+
+func (c *Client) setAuthorizer(a tokenAuthorizer) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	c.authorizer = a
+}
+
+// setAuthHeader sends the token of the current authorizer with every request, unless the request
+// sets its own Authorization header (e.g. the auth requests send none). The headers of the resty client
+// aren't changed after NewClient, the concurrent requests read them without a lock.
+func (c *Client) setAuthHeader(_ *resty.Client, r *resty.Request) error {
+	if _, ok := r.Header["Authorization"]; ok {
+		return nil
+	}
+	if token := c.auth().Token(); token != "" {
+		r.Header.Set("Authorization", token)
+	}
+	return nil
+}
+
+// authChanged updates the auth state after every new token, it's the single place used by all the auth flows:
+// the token is sent with the following requests, persisted in the AuthStore and reported to the OnAuthChange hooks.
+func (c *Client) authChanged(auth authResponse) error {
+	c.authMu.Lock()
+	c.token = auth.Token
+	c.record = auth.Record
+	hooks := slices.Clone(c.authHooks)
+	c.authMu.Unlock()
+
+	var err error
+	if auth.Token == "" {
+		err = c.store.Clear()
+	} else {
+		err = c.store.Save(AuthData{Token: auth.Token, Record: auth.Record})
+	}
+
+	var record any
+	if len(auth.Record) > 0 {
+		var m map[string]any
+		if json.Unmarshal(auth.Record, &m) == nil {
+			record = m
+		}
+	}
+	for _, fn := range hooks {
+		fn(auth.Token, record)
+	}
+
+	if err != nil {
+		return fmt.Errorf("[auth] can't save auth, err %w", err)
+	}
+	return nil
+}
+
+// authenticated switches the client to the token of an auth flow response,
+// from now on the token is refreshed in the auth collection at collectionURL.
+func (c *Client) authenticated(collectionURL string, body []byte) error {
+	var auth authResponse
+	if err := json.Unmarshal(body, &auth); err != nil {
+		return fmt.Errorf("[auth] can't unmarshal auth response, err %w", err)
+	}
+	c.useToken(collectionURL, auth.Token)
+	return c.authChanged(auth)
+}
+
+// useToken switches the client to the token authorizer with the valid token.
+func (c *Client) useToken(collectionURL string, token string) {
+	a := newAuthorizeToken(c.client, collectionURL, token)
+	a.set(token)
+	a.bind(c.store, c.authChanged)
+	c.setAuthorizer(a)
+}
+
+// useStaticToken switches the client to the non-refreshable token of the auth response.
+func (c *Client) useStaticToken(auth authResponse) error {
+	a := newAuthorizeStaticToken(auth.Token)
+	a.bind(c.store, c.authChanged)
+	c.setAuthorizer(a)
+	return c.authChanged(auth)
+}
+
+// restoreAuth binds the authorizer to the client's auth state. An anonymous client
+// is authenticated with the stored token right away, unless it's expired.
+func (c *Client) restoreAuth() {
+	if a, ok := c.auth().(boundAuthorizer); ok {
+		a.bind(c.store, c.authChanged)
+		return
+	}
+
+	// a broken store is the same as an empty one
+	auth, err := c.store.Load()
+	if err != nil || auth.Token == "" {
+		return
+	}
+	if exp := tokenExpiry(auth.Token); !exp.IsZero() && time.Now().After(exp) {
+		return
+	}
+	var record struct {
+		CollectionID string `json:"collectionId"`
+	}
+	if json.Unmarshal(auth.Record, &record) != nil || record.CollectionID == "" {
+		return
+	}
+
+	c.useToken(c.url+"/api/collections/"+record.CollectionID, auth.Token)
+	c.authMu.Lock()
+	c.token = auth.Token
+	c.record = auth.Record
+	c.authMu.Unlock()
+}
@@ -0,0 +1,103 @@
+package pocketbase
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pluja/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_OnAuthChange(t *testing.T) {
+	client := NewClient(defaultURL)
+	type change struct {
+		token  string
+		record any
+	}
+	var changes []change
+	client.OnAuthChange(func(token string, record any) {
+		changes = append(changes, change{token, record})
+	})
+
+	r, err := client.List(migrations.PostsUser, ParamsList{})
+	require.NoError(t, err)
+	assert.Zero(t, r.TotalItems)
+
+	// the token of an auth flow is used by the following requests
+	resp, err := CollectionSet[map[string]any](client, "users").AuthWithPassword(migrations.UserEmailPassword, migrations.UserEmailPassword)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, resp.Token, changes[0].token)
+	assert.Equal(t, migrations.UserEmailPassword, changes[0].record.(map[string]any)["email"])
+	assert.Equal(t, resp.Token, client.AuthStore().Token())
+	assert.Contains(t, string(client.AuthRecord()), migrations.UserEmailPassword)
+
+	r, err = client.List(migrations.PostsUser, ParamsList{})
+	require.NoError(t, err)
+	assert.NotZero(t, r.TotalItems)
+
+	require.NoError(t, client.ClearAuth())
+	require.Len(t, changes, 2)
+	assert.Equal(t, change{}, changes[1])
+	assert.Empty(t, client.AuthStore().Token())
+	assert.Nil(t, client.AuthRecord())
+
+	r, err = client.List(migrations.PostsUser, ParamsList{})
+	require.NoError(t, err)
+	assert.Zero(t, r.TotalItems)
+}
+
+func TestClient_ClearAuthConcurrentRequests(t *testing.T) {
+	client := NewClient(defaultURL)
+
+	// the requests don't race with the auth changes (go test -race)
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				_, err := client.List(migrations.PostsPublic, ParamsList{})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		require.NoError(t, client.ClearAuth())
+	}
+	wg.Wait()
+}
+
+func TestClient_ClearAuthAndLogout(t *testing.T) {
+	store := NewMemoryAuthStore()
+	client := NewClient(defaultURL,
+		WithUserEmailPassword(migrations.UserEmailPassword, migrations.UserEmailPassword),
+		WithAuthStore(store),
+	)
+	var tokens []string
+	client.OnAuthChange(func(token string, _ any) {
+		tokens = append(tokens, token)
+	})
+	require.NoError(t, client.Authorize())
+	require.Len(t, tokens, 1)
+
+	// the credentials are kept
+	require.NoError(t, client.ClearAuth())
+	stored, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, stored.Token)
+	r, err := client.List(migrations.PostsUser, ParamsList{})
+	require.NoError(t, err)
+	assert.NotZero(t, r.TotalItems)
+	assert.Len(t, tokens, 3)
+	assert.NotEmpty(t, tokens[2])
+
+	// the client is anonymous
+	require.NoError(t, client.Logout())
+	r, err = client.List(migrations.PostsUser, ParamsList{})
+	require.NoError(t, err)
+	assert.Zero(t, r.TotalItems)
+	assert.Empty(t, client.AuthStore().Token())
+	assert.Equal(t, []string{"", ""}, []string{tokens[1], tokens[3]})
+}
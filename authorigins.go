@@ -0,0 +1,56 @@
+package pocketbase
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// AuthOrigins is the service of the devices (IP and user agent fingerprints) the auth records
+	// authenticated from, i.e. the records of the "_authOrigins" system collection (PocketBase v0.23+).
+	// PocketBase sends the login alerts for the new origins.
+	//
+	// The auth records can manage their own auth origins, superusers all of them.
+	AuthOrigins struct {
+		*Collection[AuthOrigin]
+	}
+
+	AuthOrigin struct {
+		ID            string `json:"id"`
+		CollectionRef string `json:"collectionRef"`
+		RecordRef     string `json:"recordRef"`
+		Fingerprint   string `json:"fingerprint"`
+		Created       string `json:"created"`
+		Updated       string `json:"updated"`
+	}
+)
+
+// ListByRecord lists all the auth origins of the auth record, the recently used first.
+func (s AuthOrigins) ListByRecord(collectionID string, recordID string) ([]AuthOrigin, error) {
+	return s.ListByRecordCtx(context.Background(), collectionID, recordID)
+}
+
+func (s AuthOrigins) ListByRecordCtx(ctx context.Context, collectionID string, recordID string) ([]AuthOrigin, error) {
+	filters, err := recordFilter(collectionID, recordID).Build()
+	if err != nil {
+		return nil, fmt.Errorf("[auth-origins] can't list auth origins, err %w", err)
+	}
+	resp, err := s.FullListCtx(ctx, ParamsList{Filters: filters, Sort: "-updated"})
+	if err != nil {
+		return nil, fmt.Errorf("[auth-origins] can't list auth origins, err %w", err)
+	}
+	return resp.Items, nil
+}
+
+// Revoke deletes the auth origin, the next authentication from the device
+// is reported with a login alert again.
+func (s AuthOrigins) Revoke(id string) error {
+	return s.RevokeCtx(context.Background(), id)
+}
+
+func (s AuthOrigins) RevokeCtx(ctx context.Context, id string) error {
+	if err := s.DeleteCtx(ctx, id); err != nil {
+		return fmt.Errorf("[auth-origins] can't revoke the auth origin, err %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package pocketbase
+
+import (
+	"testing"
+
+	"github.com/pluja/pocketbase/filter"
+	"github.com/pluja/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthOrigins(t *testing.T) {
+	email := testAuthRecord(t, migrations.UsersOTP)
+	client := NewClient(defaultURL)
+	auth, err := CollectionSet[Record](client, migrations.UsersOTP).AuthWithPassword(email, email)
+	require.NoError(t, err)
+	collectionID, recordID := auth.Record.CollectionID, auth.Record.ID
+
+	origins, err := client.AuthOrigins().ListByRecord(collectionID, recordID)
+	require.NoError(t, err)
+	require.Len(t, origins, 1)
+	assert.NotEmpty(t, origins[0].Fingerprint)
+	assert.Equal(t, recordID, origins[0].RecordRef)
+
+	admin := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	origins, err = admin.AuthOrigins().ListByRecord(collectionID, recordID)
+	require.NoError(t, err)
+	assert.Len(t, origins, 1)
+
+	require.NoError(t, client.AuthOrigins().Revoke(origins[0].ID))
+	origins, err = client.AuthOrigins().ListByRecord(collectionID, recordID)
+	require.NoError(t, err)
+	assert.Empty(t, origins)
+
+	assert.True(t, IsNotFound(client.AuthOrigins().Revoke("non_existing_id")))
+
+	_, err = client.AuthOrigins().ListByRecord(collectionID, `invalid\`)
+	assert.ErrorIs(t, err, filter.ErrInvalidValue)
+}
@@ -1,26 +1,58 @@
 package pocketbase
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 	"golang.org/x/sync/singleflight"
 )
 
-type authStore interface {
-	authorizer
+// AuthState is the current auth token of a client.
+type AuthState interface {
+	// IsValid reports whether the token is set and doesn't need a refresh yet.
 	IsValid() bool
 	Token() string
 }
 
+type tokenAuthorizer interface {
+	authorizer
+	AuthState
+	// validUntil returns when the token should be refreshed, it's zero before the first authorization.
+	validUntil() time.Time
+	// renew replaces the token with a new one, unless it's no longer the current one.
+	renew(ctx context.Context, token string) error
+	// reset forgets the token, the next authorization gets a new one.
+	reset()
+}
+
 type authorizer interface {
-	authorize() error
+	authorize(ctx context.Context) error
+}
+
+// boundAuthorizer is implemented by the authorizers reporting their new tokens to the client,
+// the store is used to restore the token of a previous run.
+type boundAuthorizer interface {
+	bind(store AuthStore, onAuth func(auth authResponse) error)
+}
+
+type requestOTPResponse struct {
+	OTPID string `json:"otpId"`
+}
+
+// authResponse is the common part of all the auth responses.
+type authResponse struct {
+	Token  string          `json:"token"`
+	Record json.RawMessage `json:"record"`
 }
 
 type authorizeNoOp struct{}
 
-func (a authorizeNoOp) authorize() error {
+func (a authorizeNoOp) authorize(_ context.Context) error {
 	return nil
 }
 
@@ -32,17 +64,87 @@ func (a authorizeNoOp) Token() string {
 	return ""
 }
 
+func (a authorizeNoOp) validUntil() time.Time {
+	return time.Time{}
+}
+
+func (a authorizeNoOp) renew(_ context.Context, _ string) error {
+	return nil
+}
+
+func (a authorizeNoOp) reset() {}
+
+// authToken is the current token of an authorizer.
+type authToken struct {
+	mu         sync.Mutex
+	token      string
+	tokenValid time.Time
+
+	store  AuthStore
+	onAuth func(auth authResponse) error
+}
+
+func (t *authToken) bind(store AuthStore, onAuth func(auth authResponse) error) {
+	t.store = store
+	t.onAuth = onAuth
+}
+
+// update sets the token of the auth response and reports it to the client.
+func (t *authToken) update(auth authResponse) error {
+	t.set(auth.Token)
+	if t.onAuth == nil {
+		return nil
+	}
+	return t.onAuth(auth)
+}
+
+func (t *authToken) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.token = ""
+	t.tokenValid = time.Time{}
+}
+
+func (t *authToken) get() (string, time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.token, t.tokenValid
+}
+
+func (t *authToken) set(token string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.token = token
+	t.tokenValid = tokenRefreshAt(token, time.Now())
+}
+
+func (t *authToken) IsValid() bool {
+	return time.Now().Before(t.validUntil())
+}
+
+func (t *authToken) Token() string {
+	token, _ := t.get()
+	return token
+}
+
+func (t *authToken) validUntil() time.Time {
+	_, valid := t.get()
+	return valid
+}
+
+// authorizeEmailPassword logs in with the email and password and/or
+// with a one-time password sent to the email if otp is set.
 type authorizeEmailPassword struct {
+	authToken
 	email       string
 	password    string
-	token       string
-	tokenValid  time.Time
+	otp         OTPPasswordFunc
 	client      *resty.Client
-	url         string
+	url         string // auth collection URL
 	tokenSingle singleflight.Group
 }
 
-func newAuthorizeEmailPassword(c *resty.Client, url string, email string, password string) authStore {
+func newAuthorizeEmailPassword(c *resty.Client, url string, email string, password string) tokenAuthorizer {
 	return &authorizeEmailPassword{
 		client:      c,
 		email:       email,
@@ -52,52 +154,170 @@ func newAuthorizeEmailPassword(c *resty.Client, url string, email string, passwo
 	}
 }
 
-func (a *authorizeEmailPassword) authorize() error {
-	type authResponse struct {
-		Token string `json:"token"`
+func newAuthorizeOTP(c *resty.Client, url string, email string, password string, otp OTPPasswordFunc) tokenAuthorizer {
+	return &authorizeEmailPassword{
+		client:      c,
+		email:       email,
+		password:    password,
+		otp:         otp,
+		url:         url,
+		tokenSingle: singleflight.Group{},
 	}
+}
 
-	_, err, _ := a.tokenSingle.Do("auth", func() (interface{}, error) {
-		if time.Now().Before(a.tokenValid) {
-			return nil, nil
+func (a *authorizeEmailPassword) authorize(ctx context.Context) error {
+	return singleflightCtx(ctx, &a.tokenSingle, "auth", func(ctx context.Context) error {
+		token, valid := a.get()
+		if time.Now().Before(valid) {
+			return nil
 		}
-
-		resp, err := a.client.R().
-			SetHeader("Content-Type", "application/json").
-			SetBody(map[string]interface{}{
-				"identity": a.email,
-				"password": a.password,
-			}).
-			SetResult(&authResponse{}).
-			SetHeader("Authorization", "").
-			Post(a.url)
-
-		if err != nil {
-			return nil, fmt.Errorf("[auth] can't send request to pocketbase %w", err)
+		if token == "" && a.restore(ctx) {
+			return nil
 		}
+		if token != "" {
+			if auth, err := refreshToken(ctx, a.client, a.url, token); err == nil {
+				return a.update(auth)
+			}
+		}
+		return a.login(ctx)
+	})
+}
 
-		if resp.IsError() {
-			return nil, fmt.Errorf("[auth] pocketbase returned status: %d, msg: %s, err %w",
-				resp.StatusCode(),
-				resp.String(),
-				ErrInvalidResponse,
-			)
+func (a *authorizeEmailPassword) renew(ctx context.Context, token string) error {
+	return singleflightCtx(ctx, &a.tokenSingle, "auth", func(ctx context.Context) error {
+		current, _ := a.get()
+		if current != token {
+			return nil
 		}
+		if current != "" {
+			if auth, err := refreshToken(ctx, a.client, a.url, current); err == nil {
+				return a.update(auth)
+			}
+		}
+		return a.login(ctx)
+	})
+}
 
-		auth := *resp.Result().(*authResponse)
-		a.token = auth.Token
-		a.client.SetHeader("Authorization", auth.Token)
-		a.tokenValid = time.Now().Add(60 * time.Minute)
+// login authenticates with the password or the one-time password. With both of them
+// the password is the first factor of the multi-factor authentication and the one-time password the second one.
+func (a *authorizeEmailPassword) login(ctx context.Context) error {
+	ctx = withoutAuthRetry(ctx)
+	if a.otp == nil {
+		return a.loginPassword(ctx, "")
+	}
+	if a.password == "" {
+		return a.loginOTP(ctx, "")
+	}
 
-		return nil, nil
-	})
+	err := a.loginPassword(ctx, "")
+	var mfaErr *MFARequiredError
+	if errors.As(err, &mfaErr) {
+		return a.loginOTP(ctx, mfaErr.MFAID)
+	}
 	return err
 }
 
-func (a *authorizeEmailPassword) IsValid() bool {
-	return time.Now().Before(a.tokenValid)
+func (a *authorizeEmailPassword) loginPassword(ctx context.Context, mfaID string) error {
+	resp, err := a.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]interface{}{
+			"identity": a.email,
+			"password": a.password,
+			"mfaId":    mfaID,
+		}).
+		SetResult(&authResponse{}).
+		SetHeader("Authorization", "").
+		Post(a.url + "/auth-with-password")
+
+	if err != nil {
+		return fmt.Errorf("[auth] can't send request to pocketbase %w", err)
+	}
+
+	if resp.IsError() {
+		return fmt.Errorf("[auth] %w", newAuthError(resp))
+	}
+
+	return a.update(*resp.Result().(*authResponse))
+}
+
+func (a *authorizeEmailPassword) loginOTP(ctx context.Context, mfaID string) error {
+	resp, err := a.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]interface{}{
+			"email": a.email,
+		}).
+		SetResult(&requestOTPResponse{}).
+		SetHeader("Authorization", "").
+		Post(a.url + "/request-otp")
+	if err != nil {
+		return fmt.Errorf("[auth] can't send request to pocketbase %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("[auth] %w", newApiError(resp))
+	}
+
+	otpID := resp.Result().(*requestOTPResponse).OTPID
+	password, err := a.otp(ctx, otpID)
+	if err != nil {
+		return fmt.Errorf("[auth] can't get one-time password, err %w", err)
+	}
+
+	resp, err = a.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]interface{}{
+			"otpId":    otpID,
+			"password": password,
+			"mfaId":    mfaID,
+		}).
+		SetResult(&authResponse{}).
+		SetHeader("Authorization", "").
+		Post(a.url + "/auth-with-otp")
+	if err != nil {
+		return fmt.Errorf("[auth] can't send request to pocketbase %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("[auth] %w", newAuthError(resp))
+	}
+
+	return a.update(*resp.Result().(*authResponse))
 }
 
-func (a *authorizeEmailPassword) Token() string {
-	return a.token
+// restore refreshes the stored token of the same identity instead of a new login,
+// it reports whether the refresh succeeded.
+func (a *authorizeEmailPassword) restore(ctx context.Context) bool {
+	if a.store == nil {
+		return false
+	}
+	stored, err := a.store.Load()
+	if err != nil || !stored.identity(a.email) {
+		return false
+	}
+	if exp := tokenExpiry(stored.Token); !exp.IsZero() && time.Now().After(exp) {
+		return false
+	}
+	auth, err := refreshToken(ctx, a.client, a.url, stored.Token)
+	if err != nil {
+		return false
+	}
+	return a.update(auth) == nil
+}
+
+// singleflightCtx runs fn at most once at a time per key and lets every caller
+// wait for the shared result until its own context is done.
+//
+// The shared call itself is detached from the callers' cancellation,
+// so a caller that gives up doesn't fail the other waiters.
+func singleflightCtx(ctx context.Context, g *singleflight.Group, key string, fn func(ctx context.Context) error) error {
+	ch := g.DoChan(key, func() (interface{}, error) {
+		return nil, fn(context.WithoutCancel(ctx))
+	})
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res := <-ch:
+		return res.Err
+	}
 }
@@ -0,0 +1,144 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type (
+	// AuthStore persists the auth token and record of a client, e.g. to reuse them
+	// between the runs of a CLI tool or to share them between workers.
+	//
+	// Load returns an empty AuthData and no error when nothing is stored.
+	// The methods can be called concurrently.
+	AuthStore interface {
+		Save(auth AuthData) error
+		Load() (AuthData, error)
+		Clear() error
+	}
+
+	// AuthData is the persisted auth state.
+	AuthData struct {
+		Token string `json:"token"`
+		// Record is the raw JSON of the authenticated record model.
+		Record json.RawMessage `json:"record,omitempty"`
+	}
+
+	// MemoryAuthStore keeps the auth state in memory only, it's the default store of a client.
+	MemoryAuthStore struct {
+		mu   sync.Mutex
+		auth AuthData
+	}
+
+	// FileAuthStore keeps the auth state in a JSON file readable by the owner only (0600).
+	// The file is replaced atomically, so it can be shared by multiple processes.
+	FileAuthStore struct {
+		path string
+		mu   sync.Mutex
+	}
+)
+
+func NewMemoryAuthStore() *MemoryAuthStore {
+	return &MemoryAuthStore{}
+}
+
+func (s *MemoryAuthStore) Save(auth AuthData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auth = auth
+	return nil
+}
+
+func (s *MemoryAuthStore) Load() (AuthData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.auth, nil
+}
+
+func (s *MemoryAuthStore) Clear() error {
+	return s.Save(AuthData{})
+}
+
+// NewFileAuthStore returns a store of the file at path, its directory is created on the first Save.
+func NewFileAuthStore(path string) *FileAuthStore {
+	return &FileAuthStore{path: path}
+}
+
+func (s *FileAuthStore) Save(auth AuthData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(auth)
+	if err != nil {
+		return fmt.Errorf("[auth-store] can't marshal auth data, err %w", err)
+	}
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("[auth-store] can't create directory, err %w", err)
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("[auth-store] can't create file, err %w", err)
+	}
+	defer os.Remove(f.Name()) // no-op after the rename
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return fmt.Errorf("[auth-store] can't set file mode, err %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("[auth-store] can't write file, err %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("[auth-store] can't write file, err %w", err)
+	}
+	if err := os.Rename(f.Name(), s.path); err != nil {
+		return fmt.Errorf("[auth-store] can't replace file, err %w", err)
+	}
+	return nil
+}
+
+func (s *FileAuthStore) Load() (AuthData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var auth AuthData
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return auth, nil
+	}
+	if err != nil {
+		return auth, fmt.Errorf("[auth-store] can't read file, err %w", err)
+	}
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return auth, fmt.Errorf("[auth-store] can't unmarshal auth data, err %w", err)
+	}
+	return auth, nil
+}
+
+func (s *FileAuthStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("[auth-store] can't remove file, err %w", err)
+	}
+	return nil
+}
+
+// identity reports whether the stored record has the email or username.
+func (a AuthData) identity(identity string) bool {
+	var record struct {
+		Email    string `json:"email"`
+		Username string `json:"username"`
+	}
+	if a.Token == "" || json.Unmarshal(a.Record, &record) != nil {
+		return false
+	}
+	return identity != "" && (record.Email == identity || record.Username == identity)
+}
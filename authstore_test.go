@@ -0,0 +1,106 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/pluja/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthStores(t *testing.T) {
+	stores := map[string]AuthStore{
+		"memory": NewMemoryAuthStore(),
+		"file":   NewFileAuthStore(filepath.Join(t.TempDir(), "nested", "auth.json")),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			auth, err := store.Load()
+			require.NoError(t, err)
+			assert.Empty(t, auth)
+
+			want := AuthData{Token: "token", Record: json.RawMessage(`{"id":"abc"}`)}
+			require.NoError(t, store.Save(want))
+			auth, err = store.Load()
+			require.NoError(t, err)
+			assert.Equal(t, want.Token, auth.Token)
+			assert.JSONEq(t, string(want.Record), string(auth.Record))
+
+			require.NoError(t, store.Clear())
+			require.NoError(t, store.Clear())
+			auth, err = store.Load()
+			require.NoError(t, err)
+			assert.Empty(t, auth.Token)
+		})
+	}
+}
+
+func TestFileAuthStore_Mode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth.json")
+	store := NewFileAuthStore(path)
+	require.NoError(t, store.Save(AuthData{Token: "token"}))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	require.NoError(t, os.WriteFile(path, []byte("{invalid"), 0o600))
+	_, err = store.Load()
+	assert.Error(t, err)
+}
+
+func TestClient_WithAuthStore(t *testing.T) {
+	store := NewFileAuthStore(filepath.Join(t.TempDir(), "auth.json"))
+	var logins atomic.Int32
+	newClient := func() *Client {
+		c := NewClient(defaultURL,
+			WithUserEmailPassword(migrations.UserEmailPassword, migrations.UserEmailPassword),
+			WithAuthStore(store),
+		)
+		c.client.OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
+			if strings.HasSuffix(r.URL, "/auth-with-password") {
+				logins.Add(1)
+			}
+			return nil
+		})
+		return c
+	}
+
+	require.NoError(t, newClient().Authorize())
+	assert.Equal(t, int32(1), logins.Load())
+	stored, err := store.Load()
+	require.NoError(t, err)
+	assert.NotEmpty(t, stored.Token)
+	assert.Contains(t, string(stored.Record), migrations.UserEmailPassword)
+
+	// the stored token is refreshed instead of a new login
+	c := newClient()
+	require.NoError(t, c.Authorize())
+	assert.Equal(t, int32(1), logins.Load())
+	assert.NotEmpty(t, c.AuthStore().Token())
+	assert.Same(t, store, c.AuthStorage())
+	stored, err = c.AuthStorage().Load()
+	require.NoError(t, err)
+	assert.Equal(t, c.AuthStore().Token(), stored.Token)
+
+	// another identity doesn't reuse the token
+	admin := NewClient(defaultURL,
+		WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword),
+		WithAuthStore(store),
+	)
+	require.NoError(t, admin.Authorize())
+	stored, err = store.Load()
+	require.NoError(t, err)
+	assert.Contains(t, string(stored.Record), migrations.AdminEmailPassword)
+
+	// an anonymous client uses the stored token
+	anonymous := NewClient(defaultURL, WithAuthStore(store))
+	_, err = anonymous.Collections().List(ParamsList{})
+	assert.NoError(t, err)
+}
@@ -1,6 +1,7 @@
 package pocketbase
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -22,12 +23,17 @@ type (
 
 // FullList returns list with all available backup files.
 func (b Backup) FullList() ([]ResponseBackupFullList, error) {
+	return b.FullListCtx(context.Background())
+}
+
+func (b Backup) FullListCtx(ctx context.Context) ([]ResponseBackupFullList, error) {
 	var response []ResponseBackupFullList
-	if err := b.Authorize(); err != nil {
+	if err := b.AuthorizeCtx(ctx); err != nil {
 		return response, err
 	}
 
 	request := b.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json")
 
 	resp, err := request.Get(b.url + "/api/backups")
@@ -36,11 +42,7 @@ func (b Backup) FullList() ([]ResponseBackupFullList, error) {
 	}
 
 	if resp.IsError() {
-		return response, fmt.Errorf("[backup] pocketbase returned status: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return response, fmt.Errorf("[backup] %w", newApiError(resp))
 	}
 
 	if err := json.Unmarshal(resp.Body(), &response); err != nil {
@@ -56,11 +58,16 @@ type CreateRequest struct {
 
 // Create initializes a new backup.
 func (b Backup) Create(key ...string) error {
-	if err := b.Authorize(); err != nil {
+	return b.CreateCtx(context.Background(), key...)
+}
+
+func (b Backup) CreateCtx(ctx context.Context, key ...string) error {
+	if err := b.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := b.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json")
 	if len(key) > 0 {
 		request = request.SetMultipartFormData(map[string]string{
@@ -74,11 +81,7 @@ func (b Backup) Create(key ...string) error {
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("[backup] pocketbase returned status at creating a new backup: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[backup] creating a new backup: %w", newApiError(resp))
 	}
 
 	return nil
@@ -86,11 +89,16 @@ func (b Backup) Create(key ...string) error {
 
 // Upload uploads an existing backup file.
 func (b Backup) Upload(key string, reader io.Reader) error {
-	if err := b.Authorize(); err != nil {
+	return b.UploadCtx(context.Background(), key, reader)
+}
+
+func (b Backup) UploadCtx(ctx context.Context, key string, reader io.Reader) error {
+	if err := b.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := b.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetMultipartFormData(map[string]string{
 			"name": key,
@@ -103,11 +111,7 @@ func (b Backup) Upload(key string, reader io.Reader) error {
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("[backup] pocketbase returned status at uploading a new backup: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[backup] uploading a new backup: %w", newApiError(resp))
 	}
 
 	return nil
@@ -121,11 +125,16 @@ func (b Backup) Upload(key string, reader io.Reader) error {
 //	defer file.Close()
 //	_ = defaultClient.Backup().Upload("mybackup.zip", file)
 func (b Backup) Delete(key string) error {
-	if err := b.Authorize(); err != nil {
+	return b.DeleteCtx(context.Background(), key)
+}
+
+func (b Backup) DeleteCtx(ctx context.Context, key string) error {
+	if err := b.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := b.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json")
 
 	resp, err := request.Delete(b.url + "/api/backups/" + key)
@@ -134,11 +143,7 @@ func (b Backup) Delete(key string) error {
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("[backup] pocketbase returned status at deleting a backup: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[backup] deleting a backup: %w", newApiError(resp))
 	}
 
 	return nil
@@ -146,11 +151,16 @@ func (b Backup) Delete(key string) error {
 
 // Restore initializes an app data restore from an existing backup.
 func (b Backup) Restore(key string) error {
-	if err := b.Authorize(); err != nil {
+	return b.RestoreCtx(context.Background(), key)
+}
+
+func (b Backup) RestoreCtx(ctx context.Context, key string) error {
+	if err := b.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := b.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json")
 
 	u, err := url.Parse(b.url + "/api/backups/" + strings.ToLower(key) + "/restore")
@@ -163,11 +173,7 @@ func (b Backup) Restore(key string) error {
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("[backup] pocketbase returned status at creating a new backup: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[backup] restoring a backup: %w", newApiError(resp))
 	}
 
 	return nil
@@ -178,11 +184,15 @@ func (b Backup) Restore(key string) error {
 //
 // The file token can be generated via `client.Files().GetToken()`.
 func (b Backup) GetDownloadURL(token string, key string) (string, error) {
+	return b.GetDownloadURLCtx(context.Background(), token, key)
+}
+
+func (b Backup) GetDownloadURLCtx(ctx context.Context, token string, key string) (string, error) {
 	if strings.TrimSpace(token) == "" || strings.TrimSpace(key) == "" {
 		return "", fmt.Errorf("[backup] pocketbase cannot get donwload-URL because of a missing token and/or key")
 	}
 
-	if err := b.Authorize(); err != nil {
+	if err := b.AuthorizeCtx(ctx); err != nil {
 		return "", err
 	}
 
@@ -0,0 +1,233 @@
+package pocketbase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type (
+	// Batch queues create/update/upsert/delete record operations
+	// and sends them to PocketBase as a single transaction.
+	//
+	// Batch requests must be enabled in the PocketBase settings.
+	Batch struct {
+		*Client
+		requests []batchRequest
+		err      error
+	}
+
+	// BatchFile is a file uploaded together with a batch operation.
+	BatchFile struct {
+		Field  string
+		Name   string
+		Reader io.Reader
+	}
+
+	// BatchResult is the response of a single batch operation.
+	BatchResult struct {
+		Status int             `json:"status"`
+		Body   json.RawMessage `json:"body"`
+	}
+
+	// BatchError is returned when one of the batch operations fails
+	// and the whole transaction is rolled back.
+	BatchError struct {
+		// Index of the failed operation in the order they were queued.
+		Index   int
+		Code    string
+		Message string
+
+		// Response is the error returned by the failed operation.
+		Response *ApiError
+	}
+
+	batchRequest struct {
+		Method string         `json:"method"`
+		URL    string         `json:"url"`
+		Body   map[string]any `json:"body,omitempty"`
+
+		files []BatchFile
+	}
+)
+
+// Create queues a new record creation in the collection.
+func (b *Batch) Create(collection string, body any, files ...BatchFile) *Batch {
+	return b.add(http.MethodPost, "/api/collections/"+url.PathEscape(collection)+"/records", body, files)
+}
+
+// Update queues an update of the record with the given id.
+func (b *Batch) Update(collection string, id string, body any, files ...BatchFile) *Batch {
+	return b.add(http.MethodPatch, "/api/collections/"+url.PathEscape(collection)+"/records/"+url.PathEscape(id), body, files)
+}
+
+// Upsert queues an update of the record identified by the "id" body field
+// or a creation if such record doesn't exist.
+func (b *Batch) Upsert(collection string, body any, files ...BatchFile) *Batch {
+	return b.add(http.MethodPut, "/api/collections/"+url.PathEscape(collection)+"/records", body, files)
+}
+
+// Delete queues a deletion of the record with the given id.
+func (b *Batch) Delete(collection string, id string) *Batch {
+	return b.add(http.MethodDelete, "/api/collections/"+url.PathEscape(collection)+"/records/"+url.PathEscape(id), nil, nil)
+}
+
+// Len returns the number of queued operations.
+func (b *Batch) Len() int {
+	return len(b.requests)
+}
+
+func (b *Batch) add(method string, path string, body any, files []BatchFile) *Batch {
+	req := batchRequest{
+		Method: method,
+		URL:    path,
+		files:  files,
+	}
+	if body != nil {
+		m, err := toBodyMap(body)
+		if err != nil && b.err == nil {
+			b.err = fmt.Errorf("[batch] can't encode body of operation %d, err %w", len(b.requests), err)
+		}
+		req.Body = m
+	}
+	b.requests = append(b.requests, req)
+	return b
+}
+
+// Send executes all queued operations in a single transaction and returns
+// their results in the same order as they were queued.
+//
+// If any of the operations fails, nothing is persisted and *BatchError is returned.
+func (b *Batch) Send() ([]BatchResult, error) {
+	return b.SendCtx(context.Background())
+}
+
+func (b *Batch) SendCtx(ctx context.Context) ([]BatchResult, error) {
+	var response []BatchResult
+	if b.err != nil {
+		return response, b.err
+	}
+
+	if err := b.AuthorizeCtx(ctx); err != nil {
+		return response, err
+	}
+
+	payload := map[string]any{"requests": b.requests}
+
+	request := b.client.R().
+		SetContext(ctx)
+
+	hasFiles := false
+	for i, r := range b.requests {
+		for _, f := range r.files {
+			hasFiles = true
+			request.SetFileReader("requests."+strconv.Itoa(i)+"."+f.Field, f.Name, f.Reader)
+		}
+	}
+	if hasFiles {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return response, fmt.Errorf("[batch] can't encode request, err %w", err)
+		}
+		request.SetMultipartFormData(map[string]string{"@jsonPayload": string(data)})
+	} else {
+		request.
+			SetHeader("Content-Type", "application/json").
+			SetBody(payload)
+	}
+
+	resp, err := request.Post(b.url + "/api/batch")
+	if err != nil {
+		return response, fmt.Errorf("[batch] can't send batch request to pocketbase, err %w", err)
+	}
+
+	if resp.IsError() {
+		if batchErr := b.batchError(resp.Body()); batchErr != nil {
+			return response, fmt.Errorf("[batch] %w", batchErr)
+		}
+		return response, fmt.Errorf("[batch] %w", newApiError(resp))
+	}
+
+	if err := json.Unmarshal(resp.Body(), &response); err != nil {
+		return response, fmt.Errorf("[batch] can't unmarshal response, err %w", err)
+	}
+	return response, nil
+}
+
+// batchError extracts the failed operation from the batch error response, e.g.
+//
+//	{"data": {"requests": {"1": {"code": "...", "message": "...", "response": {...}}}}}
+func (b *Batch) batchError(body []byte) *BatchError {
+	var data struct {
+		Data struct {
+			Requests map[string]struct {
+				Code     string          `json:"code"`
+				Message  string          `json:"message"`
+				Response json.RawMessage `json:"response"`
+			} `json:"requests"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil
+	}
+	for key, failed := range data.Data.Requests {
+		index, err := strconv.Atoi(key)
+		if err != nil {
+			continue
+		}
+		e := &BatchError{
+			Index:   index,
+			Code:    failed.Code,
+			Message: failed.Message,
+		}
+		var status struct {
+			Status int `json:"status"`
+		}
+		_ = json.Unmarshal(failed.Response, &status)
+		e.Response = &ApiError{Status: status.Status, Raw: string(failed.Response)}
+		e.Response.parseBody(failed.Response)
+		if index >= 0 && index < len(b.requests) {
+			e.Response.Method = b.requests[index].Method
+			e.Response.URL = b.requests[index].URL
+		}
+		return e
+	}
+	return nil
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch operation %d failed: %s", e.Index, e.Response.Error())
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Response
+}
+
+// Decode unmarshals the operation response body, e.g. the created record.
+func (r BatchResult) Decode(v any) error {
+	if len(r.Body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Body, v)
+}
+
+// toBodyMap converts a struct with JSON tags (or a map) to a generic map,
+// as required by the batch request items.
+func toBodyMap(body any) (map[string]any, error) {
+	if m, ok := body.(map[string]any); ok {
+		return m, nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,90 @@
+package pocketbase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pluja/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type batchPost struct {
+	ID    string `json:"id,omitempty"`
+	Field string `json:"field"`
+}
+
+func TestBatch_Send(t *testing.T) {
+	client := NewClient(defaultURL)
+	field := "value_" + time.Now().Format(time.StampMilli)
+
+	existing, err := client.Create(migrations.PostsPublic, map[string]any{"field": field})
+	require.NoError(t, err)
+
+	t.Run("create, update, upsert and delete in one transaction", func(t *testing.T) {
+		toDelete, err := client.Create(migrations.PostsPublic, map[string]any{"field": field})
+		require.NoError(t, err)
+
+		results, err := client.Batch().
+			Create(migrations.PostsPublic, batchPost{Field: field + "_created"}).
+			Update(migrations.PostsPublic, existing.ID, map[string]any{"field": field + "_updated"}).
+			Upsert(migrations.PostsPublic, map[string]any{"field": field + "_upserted"}).
+			Delete(migrations.PostsPublic, toDelete.ID).
+			Send()
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+
+		var created batchPost
+		require.NoError(t, results[0].Decode(&created))
+		assert.NotEmpty(t, created.ID)
+		assert.Equal(t, field+"_created", created.Field)
+
+		updated, err := client.One(migrations.PostsPublic, existing.ID)
+		require.NoError(t, err)
+		assert.Equal(t, field+"_updated", updated["field"])
+
+		assert.Equal(t, 204, results[3].Status)
+		_, err = client.One(migrations.PostsPublic, toDelete.ID)
+		assert.True(t, IsNotFound(err))
+	})
+
+	t.Run("failed operation rolls back the transaction", func(t *testing.T) {
+		_, err := client.Batch().
+			Update(migrations.PostsPublic, existing.ID, map[string]any{"field": field + "_rollback"}).
+			Create(migrations.PostsPublic, map[string]any{"id": "x"}).
+			Send()
+		require.Error(t, err)
+
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		assert.Equal(t, 1, batchErr.Index)
+		assert.Equal(t, 400, batchErr.Response.Status)
+		assert.Contains(t, batchErr.Response.Data, "id")
+		assert.True(t, IsValidation(err))
+
+		record, err := client.One(migrations.PostsPublic, existing.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, field+"_rollback", record["field"])
+	})
+
+	t.Run("create with files", func(t *testing.T) {
+		results, err := client.Batch().
+			Create(migrations.PostsFiles, map[string]any{"field": field},
+				BatchFile{Field: "files", Name: "a.txt", Reader: strings.NewReader("a")},
+				BatchFile{Field: "files", Name: "b.txt", Reader: strings.NewReader("b")},
+			).
+			Send()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		var created struct {
+			Field string   `json:"field"`
+			Files []string `json:"files"`
+		}
+		require.NoError(t, results[0].Decode(&created))
+		assert.Equal(t, field, created.Field)
+		assert.Len(t, created.Files, 2)
+	})
+}
@@ -1,28 +1,33 @@
 package pocketbase
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"iter"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/duke-git/lancet/v2/convertor"
 	"github.com/go-resty/resty/v2"
 	"github.com/pocketbase/pocketbase/core"
 )
 
-var ErrInvalidResponse = errors.New("invalid response")
-
 type (
 	Client struct {
 		client     *resty.Client
 		url        string
-		authorizer authStore
+		authMu     sync.Mutex
+		authorizer tokenAuthorizer
+		record     json.RawMessage
+		authHooks  []func(token string, record any)
+		store      AuthStore
+		refreshCtx context.Context
 		token      string
 		sseDebug   bool
 		restDebug  bool
+		realtime   *realtime
 	}
 	ClientOption func(*Client)
 )
@@ -43,7 +48,10 @@ func NewClient(url string, opts ...ClientOption) *Client {
 		client:     client,
 		url:        url,
 		authorizer: authorizeNoOp{},
+		store:      NewMemoryAuthStore(),
 	}
+	c.realtime = newRealtime(c)
+	client.OnBeforeRequest(c.setAuthHeader)
 	opts = append([]ClientOption{}, opts...)
 	if EnvIsTruthy("REST_DEBUG") {
 		opts = append(opts, WithRestDebug())
@@ -55,6 +63,11 @@ func NewClient(url string, opts ...ClientOption) *Client {
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.restoreAuth()
+	client.OnAfterResponse(c.retryUnauthorized)
+	if c.refreshCtx != nil {
+		go c.refreshInBackground(c.refreshCtx)
+	}
 
 	return c
 }
@@ -74,7 +87,17 @@ func WithSseDebug() ClientOption {
 
 func WithAdminEmailPassword22(email, password string) ClientOption {
 	return func(c *Client) {
-		c.authorizer = newAuthorizeEmailPassword(c.client, c.url+"/api/admins/auth-with-password", email, password)
+		c.authorizer = newAuthorizeEmailPassword(c.client, c.url+"/api/admins", email, password)
+	}
+}
+
+// WithAuthStore sets the store persisting the auth token and record (in memory by default).
+//
+// The email/password clients refresh the stored token of the same identity instead of a new login,
+// the anonymous clients are authenticated with the stored token right away.
+func WithAuthStore(store AuthStore) ClientOption {
+	return func(c *Client) {
+		c.store = store
 	}
 }
 
@@ -94,52 +117,105 @@ func WithRetry(count int, waitTime, maxWaitTime time.Duration) ClientOption {
 	}
 }
 
+// withTransportOf shares the HTTP transport and the request settings of the other client.
+func withTransportOf(other *Client) ClientOption {
+	return func(c *Client) {
+		c.client.
+			SetTransport(other.client.GetClient().Transport).
+			SetTimeout(other.client.GetClient().Timeout).
+			SetRetryCount(other.client.RetryCount).
+			SetRetryWaitTime(other.client.RetryWaitTime).
+			SetRetryMaxWaitTime(other.client.RetryMaxWaitTime).
+			SetDebug(other.client.Debug)
+		c.sseDebug = other.sseDebug
+		c.restDebug = other.restDebug
+	}
+}
+
 func WithAdminEmailPassword(email, password string) ClientOption {
 	return func(c *Client) {
-		c.authorizer = newAuthorizeEmailPassword(c.client, c.url+fmt.Sprintf("/api/collections/%s/auth-with-password", core.CollectionNameSuperusers), email, password)
+		c.authorizer = newAuthorizeEmailPassword(c.client, c.url+"/api/collections/"+core.CollectionNameSuperusers, email, password)
 	}
 }
 
 func WithUserEmailPassword(email, password string) ClientOption {
 	return func(c *Client) {
-		c.authorizer = newAuthorizeEmailPassword(c.client, c.url+"/api/collections/users/auth-with-password", email, password)
+		c.authorizer = newAuthorizeEmailPassword(c.client, c.url+"/api/collections/users", email, password)
 	}
 }
 
 func WithUserEmailPasswordAndCollection(email, password, collection string) ClientOption {
 	return func(c *Client) {
-		c.authorizer = newAuthorizeEmailPassword(c.client, c.url+"/api/collections/"+collection+"/auth-with-password", email, password)
+		c.authorizer = newAuthorizeEmailPassword(c.client, c.url+"/api/collections/"+collection, email, password)
+	}
+}
+
+// OTPPasswordFunc returns the one-time password sent for the OTP id,
+// e.g. read from the mailbox of a service account.
+type OTPPasswordFunc func(ctx context.Context, otpID string) (string, error)
+
+// WithOTP authenticates with a one-time password sent to the email of the auth record in the collection,
+// the password is provided by fn. A new one-time password is requested only when the token can't be refreshed.
+func WithOTP(collection, email string, fn OTPPasswordFunc) ClientOption {
+	return func(c *Client) {
+		c.authorizer = newAuthorizeOTP(c.client, c.url+"/api/collections/"+collection, email, "", fn)
+	}
+}
+
+// WithAdminMFA authenticates the superuser with multi-factor authentication:
+// the password first and then the one-time password provided by fn (see WithOTP).
+func WithAdminMFA(email, password string, fn OTPPasswordFunc) ClientOption {
+	return func(c *Client) {
+		c.authorizer = newAuthorizeOTP(c.client, c.url+"/api/collections/"+core.CollectionNameSuperusers, email, password, fn)
+	}
+}
+
+// WithUserMFA is the same as WithAdminMFA, but for an auth record in the collection.
+func WithUserMFA(collection, email, password string, fn OTPPasswordFunc) ClientOption {
+	return func(c *Client) {
+		c.authorizer = newAuthorizeOTP(c.client, c.url+"/api/collections/"+collection, email, password, fn)
 	}
 }
 
 func WithAdminToken22(token string) ClientOption {
 	return func(c *Client) {
-		c.authorizer = newAuthorizeToken(c.client, c.url+"/api/admins/auth-refresh", token)
+		c.authorizer = newAuthorizeToken(c.client, c.url+"/api/admins", token)
 	}
 }
 
 func WithAdminToken(token string) ClientOption {
 	return func(c *Client) {
-		c.authorizer = newAuthorizeToken(c.client, c.url+fmt.Sprintf("/api/collections/%s/auth-refresh", core.CollectionNameSuperusers), token)
+		c.authorizer = newAuthorizeToken(c.client, c.url+"/api/collections/"+core.CollectionNameSuperusers, token)
 	}
 }
 
 func WithUserToken(token string) ClientOption {
 	return func(c *Client) {
-		c.authorizer = newAuthorizeToken(c.client, c.url+"/api/collections/users/auth-refresh", token)
+		c.authorizer = newAuthorizeToken(c.client, c.url+"/api/collections/users", token)
 	}
 }
 
 func (c *Client) Authorize() error {
-	return c.authorizer.authorize()
+	return c.AuthorizeCtx(context.Background())
+}
+
+// AuthorizeCtx is the same as Authorize, but the authorization request
+// (if any) is bound to the provided context.
+func (c *Client) AuthorizeCtx(ctx context.Context) error {
+	return c.auth().authorize(ctx)
 }
 
 func (c *Client) Update(collection string, id string, body any) error {
-	if err := c.Authorize(); err != nil {
+	return c.UpdateCtx(context.Background(), collection, id, body)
+}
+
+func (c *Client) UpdateCtx(ctx context.Context, collection string, id string, body any) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetPathParam("collection", collection).
 		SetBody(body)
@@ -149,22 +225,23 @@ func (c *Client) Update(collection string, id string, body any) error {
 		return fmt.Errorf("[update] can't send update request to pocketbase, err %w", err)
 	}
 	if resp.IsError() {
-		return fmt.Errorf("[update] pocketbase returned status: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[update] %w", newApiError(resp))
 	}
 
 	return nil
 }
 
 func (c *Client) Get(path string, result any, onRequest func(*resty.Request), onResponse func(*resty.Response)) error {
-	if err := c.Authorize(); err != nil {
+	return c.GetCtx(context.Background(), path, result, onRequest, onResponse)
+}
+
+func (c *Client) GetCtx(ctx context.Context, path string, result any, onRequest func(*resty.Request), onResponse func(*resty.Response)) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json")
 	if onRequest != nil {
 		onRequest(request)
@@ -178,11 +255,7 @@ func (c *Client) Get(path string, result any, onRequest func(*resty.Request), on
 		onResponse(resp)
 	}
 	if resp.IsError() {
-		return fmt.Errorf("[get] pocketbase returned status: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[get] %w", newApiError(resp))
 	}
 
 	if err := json.Unmarshal(resp.Body(), result); err != nil {
@@ -193,13 +266,18 @@ func (c *Client) Get(path string, result any, onRequest func(*resty.Request), on
 }
 
 func (c *Client) Create(collection string, body any) (ResponseCreate, error) {
+	return c.CreateCtx(context.Background(), collection, body)
+}
+
+func (c *Client) CreateCtx(ctx context.Context, collection string, body any) (ResponseCreate, error) {
 	var response ResponseCreate
 
-	if err := c.Authorize(); err != nil {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return response, err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetPathParam("collection", collection).
 		SetBody(body).
@@ -211,23 +289,23 @@ func (c *Client) Create(collection string, body any) (ResponseCreate, error) {
 	}
 
 	if resp.IsError() {
-		return response, fmt.Errorf("[create] pocketbase returned status: %d, msg: %s, body: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			fmt.Sprintf("%+v", body), // TODO remove that after debugging
-			ErrInvalidResponse,
-		)
+		return response, fmt.Errorf("[create] %w", newApiError(resp))
 	}
 
 	return *resp.Result().(*ResponseCreate), nil
 }
 
 func (c *Client) Delete(collection string, id string) error {
-	if err := c.Authorize(); err != nil {
+	return c.DeleteCtx(context.Background(), collection, id)
+}
+
+func (c *Client) DeleteCtx(ctx context.Context, collection string, id string) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetPathParam("collection", collection).
 		SetPathParam("id", id)
@@ -238,24 +316,25 @@ func (c *Client) Delete(collection string, id string) error {
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("[delete] pocketbase returned status: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[delete] %w", newApiError(resp))
 	}
 
 	return nil
 }
 
 func (c *Client) One(collection string, id string) (map[string]any, error) {
+	return c.OneCtx(context.Background(), collection, id)
+}
+
+func (c *Client) OneCtx(ctx context.Context, collection string, id string) (map[string]any, error) {
 	var response map[string]any
 
-	if err := c.Authorize(); err != nil {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return response, err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetPathParam("collection", collection).
 		SetPathParam("id", id)
@@ -266,11 +345,7 @@ func (c *Client) One(collection string, id string) (map[string]any, error) {
 	}
 
 	if resp.IsError() {
-		return response, fmt.Errorf("[one] pocketbase returned status: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return response, fmt.Errorf("[one] %w", newApiError(resp))
 	}
 
 	if err := json.Unmarshal(resp.Body(), &response); err != nil {
@@ -281,11 +356,16 @@ func (c *Client) One(collection string, id string) (map[string]any, error) {
 }
 
 func (c *Client) OneTo(collection string, id string, result any) error {
-	if err := c.Authorize(); err != nil {
+	return c.OneToCtx(context.Background(), collection, id, result)
+}
+
+func (c *Client) OneToCtx(ctx context.Context, collection string, id string, result any) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetPathParam("collection", collection).
 		SetPathParam("id", id)
@@ -296,11 +376,7 @@ func (c *Client) OneTo(collection string, id string, result any) error {
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("[oneTo] pocketbase returned status: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[oneTo] %w", newApiError(resp))
 	}
 
 	if err := json.Unmarshal(resp.Body(), result); err != nil {
@@ -311,34 +387,22 @@ func (c *Client) OneTo(collection string, id string, result any) error {
 }
 
 func (c *Client) List(collection string, params ParamsList) (ResponseList[map[string]any], error) {
+	return c.ListCtx(context.Background(), collection, params)
+}
+
+func (c *Client) ListCtx(ctx context.Context, collection string, params ParamsList) (ResponseList[map[string]any], error) {
 	var response ResponseList[map[string]any]
 
-	if err := c.Authorize(); err != nil {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return response, err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetPathParam("collection", collection)
 
-	if params.Page > 0 {
-		request.SetQueryParam("page", convertor.ToString(params.Page))
-	}
-	if params.Size > 0 {
-		request.SetQueryParam("perPage", convertor.ToString(params.Size))
-	}
-	if params.Filters != "" {
-		request.SetQueryParam("filter", params.Filters)
-	}
-	if params.Sort != "" {
-		request.SetQueryParam("sort", params.Sort)
-	}
-	if params.Expand != "" {
-		request.SetQueryParam("expand", params.Expand)
-	}
-	if params.Fields != "" {
-		request.SetQueryParam("fields", params.Fields)
-	}
+	params.setQueryParams(request)
 
 	resp, err := request.Get(c.url + "/api/collections/{collection}/records")
 	if err != nil {
@@ -346,11 +410,7 @@ func (c *Client) List(collection string, params ParamsList) (ResponseList[map[st
 	}
 
 	if resp.IsError() {
-		return response, fmt.Errorf("[list] pocketbase returned status: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return response, fmt.Errorf("[list] %w", newApiError(resp))
 	}
 
 	var responseRef any = &response
@@ -364,38 +424,43 @@ func (c *Client) List(collection string, params ParamsList) (ResponseList[map[st
 }
 
 func (c *Client) FullList(collection string, params ParamsList) (ResponseList[map[string]any], error) {
-	var response ResponseList[map[string]any]
-	params.Page = 1
-	params.Size = 500
+	return c.FullListCtx(context.Background(), collection, params)
+}
 
-	if err := c.Authorize(); err != nil {
-		return response, err
+func (c *Client) FullListCtx(ctx context.Context, collection string, params ParamsList) (ResponseList[map[string]any], error) {
+	if err := c.AuthorizeCtx(ctx); err != nil {
+		return ResponseList[map[string]any]{}, err
 	}
 
-	r, e := c.List(collection, params)
-	if e != nil {
-		return response, e
-	}
-	response.Items = append(response.Items, r.Items...)
-	response.Page = r.Page
-	response.PerPage = r.PerPage
-	response.TotalItems = r.TotalItems
-	response.TotalPages = r.TotalPages
+	return fullList(ctx, params, func(ctx context.Context, params ParamsList) (ResponseList[map[string]any], error) {
+		return c.ListCtx(ctx, collection, params)
+	})
+}
 
-	for i := 2; i <= r.TotalPages; i++ { // Start from page 2 because first page is already fetched
-		params.Page = i
-		r, e := c.List(collection, params)
-		if e != nil {
-			return response, e
-		}
-		response.Items = append(response.Items, r.Items...)
-	}
+// All returns an iterator over all records matching the params.
+// The pages of params.Size records (500 by default) are fetched lazily while iterating,
+// starting from params.Page, so the iteration can be stopped at any time with break.
+//
+//	for record, err := range client.All(ctx, "posts", pocketbase.ParamsList{Sort: "created"}) {
+//		if err != nil {
+//			return err
+//		}
+//		log.Print(record["id"])
+//	}
+func (c *Client) All(ctx context.Context, collection string, params ParamsList) iter.Seq2[map[string]any, error] {
+	return paginate(ctx, params, func(ctx context.Context, params ParamsList) (ResponseList[map[string]any], error) {
+		return c.ListCtx(ctx, collection, params)
+	})
+}
 
-	return response, nil
+// AuthStore returns the current auth token of the client.
+func (c *Client) AuthStore() AuthState {
+	return c.auth()
 }
 
-func (c *Client) AuthStore() authStore {
-	return c.authorizer
+// AuthStorage returns the store persisting the auth token and record, see WithAuthStore.
+func (c *Client) AuthStorage() AuthStore {
+	return c.store
 }
 
 func (c *Client) Backup() Backup {
@@ -404,8 +469,36 @@ func (c *Client) Backup() Backup {
 	}
 }
 
+// Batch returns a new builder for a transactional batch of record operations.
+func (c *Client) Batch() *Batch {
+	return &Batch{
+		Client: c,
+	}
+}
+
+// Collections returns the service for managing the collections schema.
+func (c *Client) Collections() Collections {
+	return Collections{
+		Client: c,
+	}
+}
+
 func (c *Client) Files() Files {
 	return Files{
 		Client: c,
 	}
 }
+
+// ExternalAuths returns the service for managing the OAuth2 identities linked to the auth records.
+func (c *Client) ExternalAuths() ExternalAuths {
+	return ExternalAuths{
+		Collection: CollectionSet[ExternalAuth](c, core.CollectionNameExternalAuths),
+	}
+}
+
+// AuthOrigins returns the service for managing the devices the auth records authenticated from.
+func (c *Client) AuthOrigins() AuthOrigins {
+	return AuthOrigins{
+		Collection: CollectionSet[AuthOrigin](c, core.CollectionNameAuthOrigins),
+	}
+}
@@ -1,6 +1,8 @@
 package pocketbase
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -143,6 +145,45 @@ func TestAuthorizeEmailPassword(t *testing.T) {
 	}
 }
 
+func TestAuthorizeOTP(t *testing.T) {
+	var otpIDs []string
+	email := testAuthRecord(t, migrations.UsersOTP)
+	c := NewClient(defaultURL, WithOTP(migrations.UsersOTP, email, func(ctx context.Context, otpID string) (string, error) {
+		otpIDs = append(otpIDs, otpID)
+		return testOTPPassword(ctx, otpID)
+	}))
+	require.NoError(t, c.Authorize())
+	assert.Len(t, otpIDs, 1)
+	assert.True(t, c.AuthStore().IsValid())
+	assert.Contains(t, string(c.AuthRecord()), email)
+
+	// the token is refreshed instead of a new one-time password
+	c.authorizer.(*authorizeEmailPassword).tokenValid = time.Time{}
+	require.NoError(t, c.Authorize())
+	assert.Len(t, otpIDs, 1)
+
+	failing := NewClient(defaultURL, WithOTP(migrations.UsersOTP, email, func(context.Context, string) (string, error) {
+		return "", errors.New("no mailbox")
+	}))
+	assert.ErrorContains(t, failing.Authorize(), "no mailbox")
+}
+
+func TestAuthorizeMFA(t *testing.T) {
+	email := testAuthRecord(t, migrations.UsersMFA)
+	var otpIDs []string
+	c := NewClient(defaultURL, WithUserMFA(migrations.UsersMFA, email, email, func(ctx context.Context, otpID string) (string, error) {
+		otpIDs = append(otpIDs, otpID)
+		return testOTPPassword(ctx, otpID)
+	}))
+	require.NoError(t, c.Authorize())
+	assert.Len(t, otpIDs, 1)
+	assert.Contains(t, string(c.AuthRecord()), email)
+
+	// the password alone isn't enough
+	err := NewClient(defaultURL, WithUserEmailPasswordAndCollection(email, email, migrations.UsersMFA)).Authorize()
+	assert.ErrorIs(t, err, ErrMFARequired)
+}
+
 func TestAuthorizeToken(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -451,3 +492,39 @@ func TestClient_OneTo(t *testing.T) {
 	err = client.Delete(migrations.PostsPublic, resultCreated.ID)
 	assert.NoError(t, err)
 }
+
+func TestClient_ListCtx(t *testing.T) {
+	t.Run("list with active context", func(t *testing.T) {
+		client := NewClient(defaultURL)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		got, err := client.ListCtx(ctx, migrations.PostsPublic, ParamsList{})
+		assert.NoError(t, err)
+		assert.Positive(t, got.TotalItems)
+	})
+
+	t.Run("list with canceled context", func(t *testing.T) {
+		client := NewClient(defaultURL)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.ListCtx(ctx, migrations.PostsPublic, ParamsList{})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("authorize with canceled context", func(t *testing.T) {
+		client := NewClient(defaultURL,
+			WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.ListCtx(ctx, migrations.PostsAdmin, ParamsList{})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		// the next call with a valid context must still be able to authorize
+		got, err := client.ListCtx(context.Background(), migrations.PostsAdmin, ParamsList{})
+		assert.NoError(t, err)
+		assert.Positive(t, got.TotalItems)
+	})
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pluja/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// fieldTypeGeoPoint is not yet defined in the pocketbase version used by the SDK.
+const fieldTypeGeoPoint = "geoPoint"
+
+// commonInitialisms are uppercased in the generated identifiers (e.g. "userId" -> "UserID").
+var commonInitialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "IP": true, "JSON": true,
+	"OTP": true, "MFA": true, "SQL": true, "URL": true, "URI": true, "UUID": true,
+}
+
+type generator struct {
+	pkg    string
+	system bool
+
+	collections []pocketbase.CollectionModel
+	byID        map[string]pocketbase.CollectionModel
+
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+func newGenerator(pkg string, system bool, collections []pocketbase.CollectionModel) *generator {
+	g := &generator{
+		pkg:     pkg,
+		system:  system,
+		byID:    make(map[string]pocketbase.CollectionModel),
+		imports: make(map[string]bool),
+	}
+	for _, c := range collections {
+		if !system && strings.HasPrefix(c.Name, "_") {
+			continue
+		}
+		g.collections = append(g.collections, c)
+		g.byID[c.ID] = c
+	}
+	sort.Slice(g.collections, func(i, j int) bool {
+		return g.collections[i].Name < g.collections[j].Name
+	})
+	return g
+}
+
+// Generate returns the gofmt-ed Go source with the collection name constants,
+// record structs, select enums and relation expand structs.
+func (g *generator) Generate() ([]byte, error) {
+	g.buf.Reset()
+	g.writeConstants()
+	usesGeoPoint := false
+	for _, c := range g.collections {
+		if err := g.writeCollection(c); err != nil {
+			return nil, err
+		}
+		for _, f := range c.Fields {
+			usesGeoPoint = usesGeoPoint || f.Type == fieldTypeGeoPoint
+		}
+	}
+	if usesGeoPoint {
+		g.printf("// GeoPoint is the value of a geoPoint field.\n")
+		g.printf("type GeoPoint struct {\n\tLon float64 `json:\"lon\"`\n\tLat float64 `json:\"lat\"`\n}\n\n")
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by pbgen. DO NOT EDIT.\n\npackage %s\n\n", g.pkg)
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for imp := range g.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+		out.WriteString("import (\n")
+		for _, imp := range imports {
+			fmt.Fprintf(&out, "\t%q\n", imp)
+		}
+		out.WriteString(")\n\n")
+	}
+	out.Write(g.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return out.Bytes(), fmt.Errorf("can't format generated code, err %w", err)
+	}
+	return src, nil
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) writeConstants() {
+	if len(g.collections) == 0 {
+		return
+	}
+	g.printf("// Collection names.\nconst (\n")
+	for _, c := range g.collections {
+		g.printf("\tCollection%s = %q\n", goName(c.Name), c.Name)
+	}
+	g.printf(")\n\n")
+}
+
+func (g *generator) writeCollection(c pocketbase.CollectionModel) error {
+	typeName := goName(c.Name)
+	// the struct fields by their Go name, the hardcoded ones included
+	names := map[string]string{"CollectionID": "collectionId", "CollectionName": "collectionName"}
+
+	var enums []pocketbase.CollectionField
+	var relations []pocketbase.CollectionField
+
+	g.printf("// %s is a record of the %q %s collection.\n", typeName, c.Name, c.Type)
+	g.printf("type %s struct {\n", typeName)
+	g.printf("\tCollectionID string `json:\"collectionId\"`\n")
+	g.printf("\tCollectionName string `json:\"collectionName\"`\n")
+	for _, f := range c.Fields {
+		if f.Hidden || f.Type == core.FieldTypePassword {
+			continue // never returned by the API (e.g. password and tokenKey)
+		}
+		name := goName(f.Name)
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("collection %q: the fields %q and %q have the same Go name %s", c.Name, other, f.Name, name)
+		}
+		names[name] = f.Name
+		goType := g.fieldType(typeName, f)
+		if f.Type == core.FieldTypeSelect {
+			enums = append(enums, f)
+		}
+		if f.Type == core.FieldTypeRelation {
+			if _, ok := g.byID[optionString(f, "collectionId")]; ok {
+				relations = append(relations, f)
+			}
+		}
+		g.printf("\t%s %s `json:\"%s\"`\n", name, goType, f.Name)
+	}
+	if len(relations) > 0 {
+		if other, ok := names["Expand"]; ok {
+			return fmt.Errorf("collection %q: the field %q has the same Go name as the relations Expand", c.Name, other)
+		}
+		g.printf("\tExpand *%sExpand `json:\"expand,omitempty\"`\n", typeName)
+	}
+	g.printf("}\n\n")
+
+	for _, f := range enums {
+		g.writeEnum(typeName, f)
+	}
+
+	if len(relations) > 0 {
+		g.printf("// %sExpand holds the expanded %s relations.\n", typeName, typeName)
+		g.printf("type %sExpand struct {\n", typeName)
+		for _, f := range relations {
+			target := goName(g.byID[optionString(f, "collectionId")].Name)
+			if isMultiple(f) {
+				g.printf("\t%s []%s `json:\"%s,omitempty\"`\n", goName(f.Name), target, f.Name)
+			} else {
+				g.printf("\t%s *%s `json:\"%s,omitempty\"`\n", goName(f.Name), target, f.Name)
+			}
+		}
+		g.printf("}\n\n")
+	}
+	return nil
+}
+
+func (g *generator) writeEnum(typeName string, f pocketbase.CollectionField) {
+	enumName := typeName + goName(f.Name)
+	g.printf("// %s is a value of the %s.%s select field.\n", enumName, typeName, goName(f.Name))
+	g.printf("type %s string\n\n", enumName)
+
+	values := optionStrings(f, "values")
+	if len(values) == 0 {
+		return
+	}
+	g.printf("const (\n")
+	seen := make(map[string]bool)
+	for _, v := range values {
+		name := enumName + goName(v)
+		for seen[name] {
+			name += "_"
+		}
+		seen[name] = true
+		g.printf("\t%s %s = %q\n", name, enumName, v)
+	}
+	g.printf(")\n\n")
+}
+
+func (g *generator) fieldType(typeName string, f pocketbase.CollectionField) string {
+	switch f.Type {
+	case core.FieldTypeNumber:
+		if optionBool(f, "onlyInt") {
+			return "int"
+		}
+		return "float64"
+	case core.FieldTypeBool:
+		return "bool"
+	case core.FieldTypeDate, core.FieldTypeAutodate:
+		g.imports["github.com/pocketbase/pocketbase/tools/types"] = true
+		return "types.DateTime"
+	case core.FieldTypeJSON:
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	case core.FieldTypeSelect:
+		if isMultiple(f) {
+			return "[]" + typeName + goName(f.Name)
+		}
+		return typeName + goName(f.Name)
+	case core.FieldTypeRelation, core.FieldTypeFile:
+		if isMultiple(f) {
+			return "[]string"
+		}
+		return "string"
+	case fieldTypeGeoPoint:
+		return "GeoPoint"
+	default:
+		// text, editor, email, url and unknown (custom) field types
+		return "string"
+	}
+}
+
+func isMultiple(f pocketbase.CollectionField) bool {
+	maxSelect, _ := f.Options["maxSelect"].(float64)
+	return maxSelect > 1
+}
+
+func optionBool(f pocketbase.CollectionField, key string) bool {
+	v, _ := f.Options[key].(bool)
+	return v
+}
+
+func optionString(f pocketbase.CollectionField, key string) string {
+	v, _ := f.Options[key].(string)
+	return v
+}
+
+func optionStrings(f pocketbase.CollectionField, key string) []string {
+	raw, _ := f.Options[key].([]any)
+	values := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// goName converts a collection, field or select value name to an exported Go identifier,
+// e.g. "posts_public" -> "PostsPublic", "authorId" -> "AuthorID".
+func goName(name string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for _, w := range words {
+		upper := strings.ToUpper(w)
+		if commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		rs := []rune(w)
+		b.WriteRune(unicode.ToUpper(rs[0]))
+		b.WriteString(string(rs[1:]))
+	}
+
+	result := b.String()
+	if result == "" || unicode.IsDigit([]rune(result)[0]) {
+		return "X" + result
+	}
+	return result
+}
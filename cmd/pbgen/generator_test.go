@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/pluja/pocketbase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const schema = `[
+	{"id": "c_users", "name": "users", "type": "auth", "fields": [
+		{"name": "id", "type": "text"},
+		{"name": "password", "type": "password", "hidden": true},
+		{"name": "tokenKey", "type": "text", "hidden": true, "system": true},
+		{"name": "email", "type": "email"}
+	]},
+	{"id": "c_posts", "name": "blog_posts", "type": "base", "fields": [
+		{"name": "id", "type": "text"},
+		{"name": "status", "type": "select", "maxSelect": 1, "values": ["draft", "in review"]},
+		{"name": "tags", "type": "select", "maxSelect": 3, "values": ["go"]},
+		{"name": "authorId", "type": "relation", "maxSelect": 1, "collectionId": "c_users"},
+		{"name": "editors", "type": "relation", "maxSelect": 5, "collectionId": "c_users"},
+		{"name": "orphan", "type": "relation", "maxSelect": 1, "collectionId": "c_missing"},
+		{"name": "views", "type": "number", "onlyInt": true},
+		{"name": "rating", "type": "number"},
+		{"name": "meta", "type": "json"},
+		{"name": "cover", "type": "file", "maxSelect": 1},
+		{"name": "published", "type": "date"},
+		{"name": "location", "type": "geoPoint"}
+	]},
+	{"id": "c_system", "name": "_superusers", "type": "auth", "fields": []}
+]`
+
+func TestGenerator_Generate(t *testing.T) {
+	var collections []pocketbase.CollectionModel
+	require.NoError(t, json.Unmarshal([]byte(schema), &collections))
+
+	src, err := newGenerator("models", false, collections).Generate()
+	require.NoError(t, err)
+	// ignore the gofmt alignment
+	code := regexp.MustCompile(`[ \t]+`).ReplaceAllString(string(src), " ")
+
+	assert.Contains(t, code, "package models")
+	assert.Contains(t, code, `CollectionBlogPosts = "blog_posts"`)
+	assert.Contains(t, code, `CollectionUsers = "users"`)
+	assert.NotContains(t, code, "_superusers")
+	assert.NotContains(t, code, `json:"password"`)
+	assert.NotContains(t, code, `json:"tokenKey"`)
+
+	assert.Contains(t, code, "type BlogPosts struct {")
+	assert.Contains(t, code, "Status BlogPostsStatus `json:\"status\"`")
+	assert.Contains(t, code, "Tags []BlogPostsTags `json:\"tags\"`")
+	assert.Contains(t, code, `BlogPostsStatusInReview BlogPostsStatus = "in review"`)
+	assert.Contains(t, code, "AuthorID string `json:\"authorId\"`")
+	assert.Contains(t, code, "Editors []string `json:\"editors\"`")
+	assert.Contains(t, code, "Views int `json:\"views\"`")
+	assert.Contains(t, code, "Rating float64 `json:\"rating\"`")
+	assert.Contains(t, code, "Meta json.RawMessage `json:\"meta\"`")
+	assert.Contains(t, code, "Cover string `json:\"cover\"`")
+	assert.Contains(t, code, "Published types.DateTime `json:\"published\"`")
+	assert.Contains(t, code, "Location GeoPoint `json:\"location\"`")
+	assert.Contains(t, code, "type GeoPoint struct {")
+
+	assert.Contains(t, code, "Expand *BlogPostsExpand `json:\"expand,omitempty\"`")
+	assert.Contains(t, code, "AuthorID *Users `json:\"authorId,omitempty\"`")
+	assert.Contains(t, code, "Editors []Users `json:\"editors,omitempty\"`")
+	assert.NotContains(t, code, "Orphan *")
+
+	assert.Contains(t, code, `"encoding/json"`)
+	assert.Contains(t, code, `"github.com/pocketbase/pocketbase/tools/types"`)
+}
+
+func TestGenerator_GenerateNameCollision(t *testing.T) {
+	tests := map[string]string{
+		"hardcoded field": `[{"id": "c1", "name": "posts", "type": "base", "fields": [
+			{"name": "collection_id", "type": "text"}
+		]}]`,
+		"same go name": `[{"id": "c1", "name": "posts", "type": "base", "fields": [
+			{"name": "author_id", "type": "text"},
+			{"name": "authorId", "type": "text"}
+		]}]`,
+		"expand": `[{"id": "c1", "name": "posts", "type": "base", "fields": [
+			{"name": "expand", "type": "text"},
+			{"name": "parent", "type": "relation", "maxSelect": 1, "collectionId": "c1"}
+		]}]`,
+	}
+	for name, schema := range tests {
+		t.Run(name, func(t *testing.T) {
+			var collections []pocketbase.CollectionModel
+			require.NoError(t, json.Unmarshal([]byte(schema), &collections))
+
+			_, err := newGenerator("models", false, collections).Generate()
+			assert.ErrorContains(t, err, `collection "posts"`)
+		})
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"posts_public": "PostsPublic",
+		"authorId":     "AuthorID",
+		"avatarURL":    "AvatarURL",
+		"in review":    "InReview",
+		"2fa":          "X2fa",
+		"_superusers":  "Superusers",
+		"":             "X",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, goName(in), in)
+	}
+}
@@ -0,0 +1,72 @@
+// Command pbgen generates Go structs for the PocketBase collections,
+// to be used with pocketbase.CollectionSet[T].
+//
+// Usage:
+//
+//	go run github.com/pluja/pocketbase/cmd/pbgen -url http://localhost:8090 \
+//		-email admin@admin.com -password admin@admin.com -package models -out models/collections.go
+//
+// The superuser credentials can be passed also with the PB_EMAIL and PB_PASSWORD environment variables.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/pluja/pocketbase"
+)
+
+func main() {
+	var (
+		url      = flag.String("url", "http://127.0.0.1:8090", "PocketBase URL")
+		email    = flag.String("email", os.Getenv("PB_EMAIL"), "superuser email (env PB_EMAIL)")
+		password = flag.String("password", os.Getenv("PB_PASSWORD"), "superuser password (env PB_PASSWORD)")
+		pkg      = flag.String("package", "models", "package name of the generated file")
+		out      = flag.String("out", "", "output file (defaults to stdout)")
+		system   = flag.Bool("system", false, "include system collections (e.g. _superusers)")
+	)
+	flag.Parse()
+
+	if *email == "" || *password == "" {
+		log.Fatal("superuser email and password are required")
+	}
+
+	client := pocketbase.NewClient(*url, pocketbase.WithAdminEmailPassword(*email, *password))
+	collections, err := fetchCollections(client)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := newGenerator(*pkg, *system, collections).Generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *out == "" {
+		if _, err := fmt.Fprint(os.Stdout, string(src)); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil { //nolint:gosec // generated source, readable like the other Go files
+		log.Fatal(err)
+	}
+}
+
+func fetchCollections(client *pocketbase.Client) ([]pocketbase.CollectionModel, error) {
+	var collections []pocketbase.CollectionModel
+	params := pocketbase.ParamsList{Page: 1, Size: 200, Sort: "name"}
+	for {
+		resp, err := client.Collections().List(params)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, resp.Items...)
+		if params.Page >= resp.TotalPages {
+			return collections, nil
+		}
+		params.Page++
+	}
+}
@@ -1,7 +1,14 @@
 package main
 
 import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
 	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
 
 	_ "github.com/pluja/pocketbase/migrations"
 )
@@ -9,7 +16,49 @@ import (
 func main() {
 	app := pocketbase.New()
 
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		// broadcasts a custom realtime message, used by the SDK tests
+		se.Router.POST("/api/test/broadcast", broadcast).Bind(apis.RequireSuperuserAuth())
+		// returns the last one-time password sent for the OTP id, used by the SDK tests
+		se.Router.GET("/api/test/otp/{id}", otpPassword).Bind(apis.RequireSuperuserAuth())
+		return se.Next()
+	})
+
+	// the one-time passwords are kept instead of sending them
+	app.OnMailerRecordOTPSend().BindFunc(func(e *core.MailerRecordEvent) error {
+		otpPasswords.Store(e.Meta["otpId"], e.Meta["password"])
+		return nil
+	})
+
 	if err := app.Start(); err != nil {
 		panic(err)
 	}
 }
+
+var otpPasswords sync.Map
+
+func otpPassword(e *core.RequestEvent) error {
+	password, ok := otpPasswords.Load(e.Request.PathValue("id"))
+	if !ok {
+		return e.NotFoundError("", nil)
+	}
+	return e.JSON(http.StatusOK, map[string]any{"password": password})
+}
+
+func broadcast(e *core.RequestEvent) error {
+	var body struct {
+		Topic string          `json:"topic"`
+		Data  json.RawMessage `json:"data"`
+	}
+	if err := e.BindBody(&body); err != nil {
+		return e.BadRequestError("", err)
+	}
+
+	message := subscriptions.Message{Name: body.Topic, Data: body.Data}
+	for _, client := range e.App.SubscriptionsBroker().Clients() {
+		if client.HasSubscription(body.Topic) {
+			client.Send(message)
+		}
+	}
+	return e.NoContent(http.StatusNoContent)
+}
@@ -1,8 +1,10 @@
 package pocketbase
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/url"
 )
 
@@ -21,41 +23,73 @@ func CollectionSet[T any](client *Client, collection string) *Collection[T] {
 }
 
 func (c *Collection[T]) Update(id string, body T) error {
-	return c.Client.Update(c.Name, id, body)
+	return c.UpdateCtx(context.Background(), id, body)
+}
+
+func (c *Collection[T]) UpdateCtx(ctx context.Context, id string, body T) error {
+	return c.Client.UpdateCtx(ctx, c.Name, id, body)
 }
 
 func (c *Collection[T]) Create(body T) (ResponseCreate, error) {
-	return c.Client.Create(c.Name, body)
+	return c.CreateCtx(context.Background(), body)
+}
+
+func (c *Collection[T]) CreateCtx(ctx context.Context, body T) (ResponseCreate, error) {
+	return c.Client.CreateCtx(ctx, c.Name, body)
 }
 
 func (c *Collection[T]) Delete(id string) error {
-	return c.Client.Delete(c.Name, id)
+	return c.DeleteCtx(context.Background(), id)
+}
+
+func (c *Collection[T]) DeleteCtx(ctx context.Context, id string) error {
+	return c.Client.DeleteCtx(ctx, c.Name, id)
 }
 
 func (c *Collection[T]) List(params ParamsList) (ResponseList[T], error) {
+	return c.ListCtx(context.Background(), params)
+}
+
+func (c *Collection[T]) ListCtx(ctx context.Context, params ParamsList) (ResponseList[T], error) {
 	var response ResponseList[T]
 	params.hackResponseRef = &response
 
-	_, err := c.Client.List(c.Name, params)
+	_, err := c.Client.ListCtx(ctx, c.Name, params)
 	return response, err
 }
 
 func (c *Collection[T]) FullList(params ParamsList) (ResponseList[T], error) {
-	var response ResponseList[T]
-	params.hackResponseRef = &response
+	return c.FullListCtx(context.Background(), params)
+}
 
-	_, err := c.Client.FullList(c.Name, params)
-	return response, err
+func (c *Collection[T]) FullListCtx(ctx context.Context, params ParamsList) (ResponseList[T], error) {
+	if err := c.AuthorizeCtx(ctx); err != nil {
+		return ResponseList[T]{}, err
+	}
+
+	return fullList(ctx, params, c.ListCtx)
+}
+
+// All returns an iterator over all records matching the params.
+// The pages of params.Size records (500 by default) are fetched lazily while iterating,
+// starting from params.Page, so the iteration can be stopped at any time with break.
+func (c *Collection[T]) All(ctx context.Context, params ParamsList) iter.Seq2[T, error] {
+	return paginate(ctx, params, c.ListCtx)
 }
 
 func (c *Collection[T]) One(id string) (T, error) {
+	return c.OneCtx(context.Background(), id)
+}
+
+func (c *Collection[T]) OneCtx(ctx context.Context, id string) (T, error) {
 	var response T
 
-	if err := c.Authorize(); err != nil {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return response, err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetPathParam("collection", c.Name).
 		SetPathParam("id", id)
@@ -66,11 +100,7 @@ func (c *Collection[T]) One(id string) (T, error) {
 	}
 
 	if resp.IsError() {
-		return response, fmt.Errorf("[one] pocketbase returned status: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return response, fmt.Errorf("[one] %w", newApiError(resp))
 	}
 
 	if err := json.Unmarshal(resp.Body(), &response); err != nil {
@@ -81,13 +111,18 @@ func (c *Collection[T]) One(id string) (T, error) {
 
 // Get one record with params (only fields and expand supported)
 func (c *Collection[T]) OneWithParams(id string, params ParamsList) (T, error) {
+	return c.OneWithParamsCtx(context.Background(), id, params)
+}
+
+func (c *Collection[T]) OneWithParamsCtx(ctx context.Context, id string, params ParamsList) (T, error) {
 	var response T
 
-	if err := c.Authorize(); err != nil {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return response, err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetPathParam("collection", c.Name).
 		SetPathParam("id", id).
@@ -100,11 +135,7 @@ func (c *Collection[T]) OneWithParams(id string, params ParamsList) (T, error) {
 	}
 
 	if resp.IsError() {
-		return response, fmt.Errorf("[one] pocketbase returned status: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return response, fmt.Errorf("[one] %w", newApiError(resp))
 	}
 
 	if err := json.Unmarshal(resp.Body(), &response); err != nil {
@@ -1,9 +1,13 @@
 package pocketbase
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/go-resty/resty/v2"
+	"github.com/pluja/pocketbase/filter"
 	"github.com/pluja/pocketbase/migrations"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -227,3 +231,176 @@ func TestCollection_One(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, field+"_updated", item["field"])
 }
+
+func TestCollection_FullList(t *testing.T) {
+	client := NewClient(defaultURL)
+	field := "fulllist_" + time.Now().Format(time.StampMilli)
+	collection := CollectionSet[map[string]any](client, migrations.PostsPublic)
+
+	for i := 0; i < 5; i++ {
+		r, err := collection.Create(map[string]any{"field": field})
+		require.NoError(t, err)
+		defer func() { _ = collection.Delete(r.ID) }()
+	}
+
+	t.Run("all pages are merged", func(t *testing.T) {
+		resp, err := collection.FullList(ParamsList{Size: 2, Filters: "field='" + field + "'"})
+		require.NoError(t, err)
+		assert.Len(t, resp.Items, 5)
+		assert.Equal(t, 5, resp.TotalItems)
+		assert.Equal(t, 3, resp.TotalPages)
+	})
+
+	t.Run("all pages are merged with skipped total", func(t *testing.T) {
+		resp, err := collection.FullList(ParamsList{Size: 2, SkipTotal: true, Filters: "field='" + field + "'"})
+		require.NoError(t, err)
+		assert.Len(t, resp.Items, 5)
+		assert.Equal(t, -1, resp.TotalItems)
+	})
+
+	t.Run("concurrent pages keep the sort order", func(t *testing.T) {
+		params := ParamsList{Size: 1, Sort: "-id", Filters: "field='" + field + "'"}
+		sequential, err := collection.FullList(params)
+		require.NoError(t, err)
+
+		params.Concurrency = 3
+		concurrent, err := collection.FullList(params)
+		require.NoError(t, err)
+		assert.Len(t, concurrent.Items, 5)
+		assert.Equal(t, sequential.Items, concurrent.Items)
+	})
+}
+
+func TestCollection_All(t *testing.T) {
+	client := NewClient(defaultURL)
+	field := "all_" + time.Now().Format(time.StampMilli)
+	collection := CollectionSet[map[string]any](client, migrations.PostsPublic)
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		r, err := collection.Create(map[string]any{"field": field})
+		require.NoError(t, err)
+		ids = append(ids, r.ID)
+		defer func() { _ = collection.Delete(r.ID) }()
+	}
+
+	for _, skipTotal := range []bool{false, true} {
+		t.Run(fmt.Sprintf("iterate all pages, skipTotal=%v", skipTotal), func(t *testing.T) {
+			params := ParamsList{Size: 2, Sort: "id", SkipTotal: skipTotal, Filters: "field='" + field + "'"}
+			var got []string
+			for item, err := range collection.All(context.Background(), params) {
+				require.NoError(t, err)
+				got = append(got, item["id"].(string))
+			}
+			assert.ElementsMatch(t, ids, got)
+		})
+	}
+
+	t.Run("stop iteration with break", func(t *testing.T) {
+		params := ParamsList{Size: 2, Filters: "field='" + field + "'"}
+		count := 0
+		for _, err := range collection.All(context.Background(), params) {
+			require.NoError(t, err)
+			count++
+			if count == 3 {
+				break
+			}
+		}
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("error is yielded", func(t *testing.T) {
+		params := ParamsList{Filters: "field~~~invalid'"}
+		count := 0
+		for _, err := range collection.All(context.Background(), params) {
+			assert.Error(t, err)
+			count++
+		}
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestCollection_ListCursor(t *testing.T) {
+	client := NewClient(defaultURL)
+	field := "cursor_" + time.Now().Format(time.StampMilli)
+	collection := CollectionSet[map[string]any](client, migrations.PostsPublic)
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		r, err := collection.Create(map[string]any{"field": field})
+		require.NoError(t, err)
+		ids = append(ids, r.ID)
+		defer func() { _ = collection.Delete(r.ID) }()
+	}
+
+	params := ParamsList{Size: 2, Sort: "-id", Filters: "field='" + field + "'"}
+	var got []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 5)
+		resp, err := collection.ListCursor(cursor, params)
+		require.NoError(t, err)
+		for _, item := range resp.Items {
+			got = append(got, item["id"].(string))
+		}
+		if pages == 0 {
+			// records inserted before the cursor don't shift the following pages
+			r, err := collection.Create(map[string]any{"field": field, "id": "zzzzzzzzzzzzzzz"})
+			require.NoError(t, err)
+			defer func() { _ = collection.Delete(r.ID) }()
+		}
+		if resp.NextCursor == "" {
+			break
+		}
+		cursor = resp.NextCursor
+	}
+	assert.ElementsMatch(t, ids, got)
+	assert.IsDecreasing(t, got)
+
+	_, err := collection.ListCursor("invalid", params)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	// the page size is capped by the server
+	client.client.OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
+		r.SetQueryParam("perPage", "2")
+		return nil
+	})
+	params.Size = 5000
+	resp, err := collection.ListCursor("", params)
+	require.NoError(t, err)
+	assert.Len(t, resp.Items, 2)
+	assert.NotEmpty(t, resp.NextCursor)
+}
+
+func TestCollection_ListFilterBuilder(t *testing.T) {
+	client := NewClient(defaultURL)
+	field := `it's\'_` + time.Now().Format(time.StampMilli)
+	collection := CollectionSet[map[string]any](client, migrations.PostsPublic)
+
+	r, err := collection.Create(map[string]any{"field": field})
+	require.NoError(t, err)
+	defer func() { _ = collection.Delete(r.ID) }()
+
+	filters, err := filter.And(
+		filter.Eq("field", field),
+		filter.In("id", r.ID, "other"),
+	).Build()
+	require.NoError(t, err)
+	resp, err := collection.List(ParamsList{Filters: filters})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, r.ID, resp.Items[0]["id"])
+
+	// injection attempt is matched as a plain value
+	filters, err = filter.Filter("field = {:field}", map[string]any{
+		"field": "x' || id != '",
+	})
+	require.NoError(t, err)
+	resp, err = collection.List(ParamsList{Filters: filters})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Items)
+
+	// the trailing backslash would escape the closing quote
+	_, err = filter.Filter("field = {:a} && id = {:b}", map[string]any{"a": `\`, "b": ` || id != "" //`})
+	assert.ErrorIs(t, err, filter.ErrInvalidValue)
+}
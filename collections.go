@@ -0,0 +1,388 @@
+package pocketbase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+type (
+	// Collections manages the collections schema (superuser only).
+	Collections struct {
+		*Client
+	}
+
+	// CollectionModel describes a single collection schema.
+	CollectionModel struct {
+		ID         string            `json:"id,omitempty"`
+		Name       string            `json:"name"`
+		Type       string            `json:"type"`
+		System     bool              `json:"system"`
+		Fields     []CollectionField `json:"fields"`
+		Indexes    []string          `json:"indexes"`
+		ListRule   *string           `json:"listRule"`
+		ViewRule   *string           `json:"viewRule"`
+		CreateRule *string           `json:"createRule"`
+		UpdateRule *string           `json:"updateRule"`
+		DeleteRule *string           `json:"deleteRule"`
+		Created    string            `json:"created,omitempty"`
+		Updated    string            `json:"updated,omitempty"`
+
+		// Options holds the collection type specific settings,
+		// e.g. "viewQuery" for view collections or "authRule", "passwordAuth",
+		// "oauth2", "otp", "mfa" for auth collections.
+		Options map[string]any `json:"-"`
+	}
+
+	// CollectionField describes a single collection field.
+	CollectionField struct {
+		ID          string `json:"id,omitempty"`
+		Name        string `json:"name"`
+		Type        string `json:"type"`
+		System      bool   `json:"system"`
+		Hidden      bool   `json:"hidden"`
+		Presentable bool   `json:"presentable"`
+		Required    bool   `json:"required"`
+
+		// Options holds the field type specific settings,
+		// e.g. "values" and "maxSelect" for select fields or "collectionId" for relation fields.
+		Options map[string]any `json:"-"`
+	}
+)
+
+// Rule returns a pointer to the API rule expression.
+//
+// A nil rule allows the action only to superusers, an empty rule allows it to everyone.
+func Rule(expr string) *string {
+	return &expr
+}
+
+// Field returns the collection field with the given name.
+func (m CollectionModel) Field(name string) (CollectionField, bool) {
+	for _, f := range m.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return CollectionField{}, false
+}
+
+func (m CollectionModel) MarshalJSON() ([]byte, error) {
+	type alias CollectionModel
+	return marshalWithOptions(alias(m), m.Options)
+}
+
+func (m *CollectionModel) UnmarshalJSON(data []byte) error {
+	type alias CollectionModel
+	var a alias
+	options, err := unmarshalWithOptions(data, &a)
+	if err != nil {
+		return err
+	}
+	*m = CollectionModel(a)
+	m.Options = options
+	return nil
+}
+
+func (f CollectionField) MarshalJSON() ([]byte, error) {
+	type alias CollectionField
+	return marshalWithOptions(alias(f), f.Options)
+}
+
+func (f *CollectionField) UnmarshalJSON(data []byte) error {
+	type alias CollectionField
+	var a alias
+	options, err := unmarshalWithOptions(data, &a)
+	if err != nil {
+		return err
+	}
+	*f = CollectionField(a)
+	f.Options = options
+	return nil
+}
+
+// List returns a paginated collections list.
+func (c Collections) List(params ParamsList) (ResponseList[CollectionModel], error) {
+	return c.ListCtx(context.Background(), params)
+}
+
+func (c Collections) ListCtx(ctx context.Context, params ParamsList) (ResponseList[CollectionModel], error) {
+	var response ResponseList[CollectionModel]
+	if err := c.AuthorizeCtx(ctx); err != nil {
+		return response, err
+	}
+
+	request := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json")
+	params.setQueryParams(request)
+
+	resp, err := request.Get(c.url + "/api/collections")
+	if err != nil {
+		return response, fmt.Errorf("[collections] can't send list request to pocketbase, err %w", err)
+	}
+
+	if resp.IsError() {
+		return response, fmt.Errorf("[collections] %w", newApiError(resp))
+	}
+
+	if err := json.Unmarshal(resp.Body(), &response); err != nil {
+		return response, fmt.Errorf("[collections] can't unmarshal response, err %w", err)
+	}
+	return response, nil
+}
+
+// One returns a single collection by its id or name.
+func (c Collections) One(idOrName string) (CollectionModel, error) {
+	return c.OneCtx(context.Background(), idOrName)
+}
+
+func (c Collections) OneCtx(ctx context.Context, idOrName string) (CollectionModel, error) {
+	var response CollectionModel
+	if err := c.AuthorizeCtx(ctx); err != nil {
+		return response, err
+	}
+
+	request := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json")
+
+	resp, err := request.Get(c.url + "/api/collections/" + url.PathEscape(idOrName))
+	if err != nil {
+		return response, fmt.Errorf("[collections] can't send view request to pocketbase, err %w", err)
+	}
+
+	if resp.IsError() {
+		return response, fmt.Errorf("[collections] %w", newApiError(resp))
+	}
+
+	if err := json.Unmarshal(resp.Body(), &response); err != nil {
+		return response, fmt.Errorf("[collections] can't unmarshal response, err %w", err)
+	}
+	return response, nil
+}
+
+// Create creates a new collection and returns the created model.
+func (c Collections) Create(collection CollectionModel) (CollectionModel, error) {
+	return c.CreateCtx(context.Background(), collection)
+}
+
+func (c Collections) CreateCtx(ctx context.Context, collection CollectionModel) (CollectionModel, error) {
+	var response CollectionModel
+	if err := c.AuthorizeCtx(ctx); err != nil {
+		return response, err
+	}
+
+	request := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(collection)
+
+	resp, err := request.Post(c.url + "/api/collections")
+	if err != nil {
+		return response, fmt.Errorf("[collections] can't send create request to pocketbase, err %w", err)
+	}
+
+	if resp.IsError() {
+		return response, fmt.Errorf("[collections] %w", newApiError(resp))
+	}
+
+	if err := json.Unmarshal(resp.Body(), &response); err != nil {
+		return response, fmt.Errorf("[collections] can't unmarshal response, err %w", err)
+	}
+	return response, nil
+}
+
+// Replace replaces the collection identified by id or name with the provided model
+// and returns the updated model.
+//
+// The whole model is sent, so modify the one returned by One: the missing fields and indexes
+// are removed and the nil rules allow the actions only to superusers.
+func (c Collections) Replace(idOrName string, collection CollectionModel) (CollectionModel, error) {
+	return c.ReplaceCtx(context.Background(), idOrName, collection)
+}
+
+func (c Collections) ReplaceCtx(ctx context.Context, idOrName string, collection CollectionModel) (CollectionModel, error) {
+	var response CollectionModel
+	if err := c.AuthorizeCtx(ctx); err != nil {
+		return response, err
+	}
+
+	request := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(collection)
+
+	resp, err := request.Patch(c.url + "/api/collections/" + url.PathEscape(idOrName))
+	if err != nil {
+		return response, fmt.Errorf("[collections] can't send replace request to pocketbase, err %w", err)
+	}
+
+	if resp.IsError() {
+		return response, fmt.Errorf("[collections] %w", newApiError(resp))
+	}
+
+	if err := json.Unmarshal(resp.Body(), &response); err != nil {
+		return response, fmt.Errorf("[collections] can't unmarshal response, err %w", err)
+	}
+	return response, nil
+}
+
+// Delete deletes a single collection by its id or name.
+func (c Collections) Delete(idOrName string) error {
+	return c.DeleteCtx(context.Background(), idOrName)
+}
+
+func (c Collections) DeleteCtx(ctx context.Context, idOrName string) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
+		return err
+	}
+
+	request := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json")
+
+	resp, err := request.Delete(c.url + "/api/collections/" + url.PathEscape(idOrName))
+	if err != nil {
+		return fmt.Errorf("[collections] can't send delete request to pocketbase, err %w", err)
+	}
+
+	if resp.IsError() {
+		return fmt.Errorf("[collections] %w", newApiError(resp))
+	}
+	return nil
+}
+
+// Truncate deletes all records of the collection (including their files and cascade relations).
+func (c Collections) Truncate(idOrName string) error {
+	return c.TruncateCtx(context.Background(), idOrName)
+}
+
+func (c Collections) TruncateCtx(ctx context.Context, idOrName string) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
+		return err
+	}
+
+	request := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json")
+
+	resp, err := request.Delete(c.url + "/api/collections/" + url.PathEscape(idOrName) + "/truncate")
+	if err != nil {
+		return fmt.Errorf("[collections] can't send truncate request to pocketbase, err %w", err)
+	}
+
+	if resp.IsError() {
+		return fmt.Errorf("[collections] truncate: %w", newApiError(resp))
+	}
+	return nil
+}
+
+// Import creates or replaces the provided collections in a single transaction.
+//
+// If deleteMissing is true, all collections (and their records) that are not
+// present in the imported list are deleted.
+func (c Collections) Import(collections []CollectionModel, deleteMissing bool) error {
+	return c.ImportCtx(context.Background(), collections, deleteMissing)
+}
+
+func (c Collections) ImportCtx(ctx context.Context, collections []CollectionModel, deleteMissing bool) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
+		return err
+	}
+
+	request := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"collections":   collections,
+			"deleteMissing": deleteMissing,
+		})
+
+	resp, err := request.Put(c.url + "/api/collections/import")
+	if err != nil {
+		return fmt.Errorf("[collections] can't send import request to pocketbase, err %w", err)
+	}
+
+	if resp.IsError() {
+		return fmt.Errorf("[collections] import: %w", newApiError(resp))
+	}
+	return nil
+}
+
+// Scaffolds returns the default models of every collection type ("base", "auth", "view"),
+// useful as a starting point for new collections.
+func (c Collections) Scaffolds() (map[string]CollectionModel, error) {
+	return c.ScaffoldsCtx(context.Background())
+}
+
+func (c Collections) ScaffoldsCtx(ctx context.Context) (map[string]CollectionModel, error) {
+	var response map[string]CollectionModel
+	if err := c.AuthorizeCtx(ctx); err != nil {
+		return response, err
+	}
+
+	request := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json")
+
+	resp, err := request.Get(c.url + "/api/collections/meta/scaffolds")
+	if err != nil {
+		return response, fmt.Errorf("[collections] can't send scaffolds request to pocketbase, err %w", err)
+	}
+
+	if resp.IsError() {
+		return response, fmt.Errorf("[collections] scaffolds: %w", newApiError(resp))
+	}
+
+	if err := json.Unmarshal(resp.Body(), &response); err != nil {
+		return response, fmt.Errorf("[collections] can't unmarshal response, err %w", err)
+	}
+	return response, nil
+}
+
+// marshalWithOptions marshals v and merges the options into the same JSON object.
+// Fields of v take precedence over the options.
+func marshalWithOptions(v any, options map[string]any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(options) == 0 {
+		return data, err
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	for k, o := range options {
+		if _, ok := m[k]; !ok {
+			m[k] = o
+		}
+	}
+	return json.Marshal(m)
+}
+
+// unmarshalWithOptions unmarshals data into v and returns all JSON keys
+// that don't have a matching field in v.
+func unmarshalWithOptions(data []byte, v any) (map[string]any, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(v).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		delete(m, name)
+	}
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
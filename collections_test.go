@@ -0,0 +1,130 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pluja/pocketbase/migrations"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollections_List(t *testing.T) {
+	t.Run("without authorization", func(t *testing.T) {
+		defaultClient := NewClient(defaultURL)
+		_, err := defaultClient.Collections().List(ParamsList{})
+		assert.Error(t, err)
+		assert.True(t, IsUnauthorized(err), err)
+	})
+
+	t.Run("with valid authorization", func(t *testing.T) {
+		defaultClient := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+		resp, err := defaultClient.Collections().List(ParamsList{Filters: "name='" + migrations.PostsPublic + "'"})
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 1)
+
+		posts := resp.Items[0]
+		assert.Equal(t, migrations.PostsPublic, posts.Name)
+		assert.Equal(t, core.CollectionTypeBase, posts.Type)
+		require.NotNil(t, posts.ListRule)
+		assert.Equal(t, "", *posts.ListRule)
+		field, ok := posts.Field("field")
+		require.True(t, ok)
+		assert.Equal(t, core.FieldTypeText, field.Type)
+		assert.Contains(t, field.Options, "max")
+	})
+}
+
+func TestCollections_CRUD(t *testing.T) {
+	defaultClient := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	collections := defaultClient.Collections()
+	name := "tmp_" + time.Now().Format("20060102150405")
+
+	created, err := collections.Create(CollectionModel{
+		Name:     name,
+		Type:     core.CollectionTypeBase,
+		ListRule: Rule(""),
+		Fields: []CollectionField{
+			{Name: "title", Type: core.FieldTypeText, Required: true},
+			{Name: "status", Type: core.FieldTypeSelect, Options: map[string]any{
+				"values":    []string{"draft", "published"},
+				"maxSelect": 1,
+			}},
+		},
+	})
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, collections.Delete(name))
+		_, err := collections.One(name)
+		assert.True(t, IsNotFound(err))
+	}()
+	assert.NotEmpty(t, created.ID)
+	assert.Nil(t, created.CreateRule)
+
+	status, ok := created.Field("status")
+	require.True(t, ok)
+	assert.Equal(t, []any{"draft", "published"}, status.Options["values"])
+
+	t.Run("replace", func(t *testing.T) {
+		model, err := collections.One(created.ID)
+		require.NoError(t, err)
+
+		model.CreateRule = Rule("")
+		model.Indexes = append(model.Indexes, "CREATE INDEX idx_title ON "+name+" (title)")
+		updated, err := collections.Replace(name, model)
+		require.NoError(t, err)
+		require.NotNil(t, updated.CreateRule)
+		assert.Len(t, updated.Indexes, 1)
+
+		// options are kept untouched on round trip
+		status, ok := updated.Field("status")
+		require.True(t, ok)
+		assert.EqualValues(t, 1, status.Options["maxSelect"])
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		_, err := defaultClient.Create(name, map[string]any{"title": "foo", "status": "draft"})
+		require.NoError(t, err)
+
+		require.NoError(t, collections.Truncate(name))
+
+		list, err := defaultClient.List(name, ParamsList{})
+		require.NoError(t, err)
+		assert.Equal(t, 0, list.TotalItems)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		model, err := collections.One(name)
+		require.NoError(t, err)
+
+		model.Fields = append(model.Fields, CollectionField{Name: "imported", Type: core.FieldTypeBool})
+		require.NoError(t, collections.Import([]CollectionModel{model}, false))
+
+		model, err = collections.One(name)
+		require.NoError(t, err)
+		_, ok := model.Field("imported")
+		assert.True(t, ok)
+	})
+}
+
+func TestCollections_Scaffolds(t *testing.T) {
+	defaultClient := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	scaffolds, err := defaultClient.Collections().Scaffolds()
+	require.NoError(t, err)
+
+	auth, ok := scaffolds[core.CollectionTypeAuth]
+	require.True(t, ok)
+	_, ok = auth.Field("email")
+	assert.True(t, ok)
+	assert.Contains(t, auth.Options, "passwordAuth")
+
+	// auth options are serialized back next to the common fields
+	data, err := json.Marshal(auth)
+	require.NoError(t, err)
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Contains(t, raw, "passwordAuth")
+	assert.Contains(t, raw, "fields")
+}
@@ -0,0 +1,198 @@
+package pocketbase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pluja/pocketbase/filter"
+)
+
+// defaultCursorSort is the key used by the keyset pagination when ParamsList.Sort is empty.
+const defaultCursorSort = "id"
+
+type (
+	// cursorToken is the decoded form of ResponseCursor.NextCursor,
+	// it holds the sort key values of the last returned record.
+	cursorToken struct {
+		Sort   string `json:"s"`
+		Values []any  `json:"v"`
+	}
+
+	cursorKey struct {
+		field string
+		desc  bool
+	}
+)
+
+// ListCursor returns the page of records that follows the cursor, using the keyset pagination.
+//
+// Instead of the page offset the records are filtered by the sort key of the last record
+// of the previous page, so deep pages stay fast and records inserted or deleted during the
+// listing don't cause duplicates or skips. Pass an empty cursor to get the first page and
+// ResponseCursor.NextCursor to get the following ones, until it's empty.
+//
+// params.Sort is the key, e.g. "-created,id", only plain record fields are supported and
+// "id" is appended as the tiebreaker if missing ("id" when empty). The cursor must be used with
+// the same sort, params.Page is ignored and params.Fields must include all the key fields.
+func (c *Collection[T]) ListCursor(cursor string, params ParamsList) (ResponseCursor[T], error) {
+	return c.ListCursorCtx(context.Background(), cursor, params)
+}
+
+func (c *Collection[T]) ListCursorCtx(ctx context.Context, cursor string, params ParamsList) (ResponseCursor[T], error) {
+	var response ResponseCursor[T]
+
+	keys, sort, err := parseCursorSort(params.Sort)
+	if err != nil {
+		return response, err
+	}
+
+	if cursor != "" {
+		values, err := decodeCursor(cursor, sort, len(keys))
+		if err != nil {
+			return response, err
+		}
+		keyset, err := keysetFilter(keys, values)
+		if err != nil {
+			return response, fmt.Errorf("[cursor] can't filter the records after the cursor, err %w", err)
+		}
+		if params.Filters != "" {
+			keyset = "(" + params.Filters + ") && " + keyset
+		}
+		params.Filters = keyset
+	}
+	params.Sort = sort
+	params.Page = 1
+	params.SkipTotal = true
+	if params.Size <= 0 {
+		params.Size = defaultPageSize
+	}
+
+	r, err := c.ListCtx(ctx, params)
+	if err != nil {
+		return response, err
+	}
+	response.Items = r.Items
+
+	// the size applied by the server, PocketBase limits it to 1000
+	perPage := r.PerPage
+	if perPage <= 0 {
+		perPage = params.Size
+	}
+	if len(r.Items) == 0 || len(r.Items) < perPage {
+		return response, nil
+	}
+	response.NextCursor, err = encodeCursor(r.Items[len(r.Items)-1], keys, sort)
+	if err != nil {
+		return response, err
+	}
+	return response, nil
+}
+
+// parseCursorSort parses the sort key and returns it in the normalized form.
+func parseCursorSort(sort string) ([]cursorKey, string, error) {
+	if strings.TrimSpace(sort) == "" {
+		sort = defaultCursorSort
+	}
+
+	var keys []cursorKey
+	var normalized []string
+	hasID := false
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		key := cursorKey{field: strings.TrimPrefix(part, "+")}
+		if strings.HasPrefix(part, "-") {
+			key = cursorKey{field: part[1:], desc: true}
+		}
+		if !isPlainField(key.field) {
+			return nil, "", fmt.Errorf("[cursor] unsupported sort key %q: %w", part, ErrInvalidCursor)
+		}
+		hasID = hasID || key.field == "id"
+		keys = append(keys, key)
+		if key.desc {
+			normalized = append(normalized, "-"+key.field)
+		} else {
+			normalized = append(normalized, key.field)
+		}
+	}
+	if !hasID {
+		keys = append(keys, cursorKey{field: "id"})
+		normalized = append(normalized, "id")
+	}
+	return keys, strings.Join(normalized, ","), nil
+}
+
+func isPlainField(field string) bool {
+	if field == "" {
+		return false
+	}
+	for _, r := range field {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// keysetFilter returns the filter matching the records after the key values, e.g. for "-created,id":
+//
+//	(created < 'v1' || (created = 'v1' && id > 'v2'))
+func keysetFilter(keys []cursorKey, values []any) (string, error) {
+	or := make([]filter.Expr, 0, len(keys))
+	for i, key := range keys {
+		and := make([]filter.Expr, 0, i+1)
+		for j := 0; j < i; j++ {
+			and = append(and, filter.Eq(keys[j].field, values[j]))
+		}
+		if key.desc {
+			and = append(and, filter.Lt(key.field, values[i]))
+		} else {
+			and = append(and, filter.Gt(key.field, values[i]))
+		}
+		or = append(or, filter.And(and...))
+	}
+	return filter.Or(or...).Build()
+}
+
+func encodeCursor(record any, keys []cursorKey, sort string) (string, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("[cursor] can't encode record, err %w", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", fmt.Errorf("[cursor] can't decode record fields, err %w", err)
+	}
+
+	c := cursorToken{Sort: sort}
+	for _, key := range keys {
+		v, ok := fields[key.field]
+		if !ok {
+			return "", fmt.Errorf("[cursor] record has no %q sort key field: %w", key.field, ErrInvalidCursor)
+		}
+		c.Values = append(c.Values, v)
+	}
+
+	data, err = json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("[cursor] can't encode cursor, err %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(s string, sort string, keys int) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("[cursor] %w", ErrInvalidCursor)
+	}
+	var c cursorToken
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("[cursor] %w", ErrInvalidCursor)
+	}
+	if c.Sort != sort || len(c.Values) != keys {
+		return nil, fmt.Errorf("[cursor] cursor doesn't match sort %q: %w", sort, ErrInvalidCursor)
+	}
+	return c.Values, nil
+}
@@ -0,0 +1,77 @@
+package pocketbase
+
+import (
+	"testing"
+
+	"github.com/pluja/pocketbase/filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysetFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		sort   string
+		values []any
+		want   string
+		norm   string
+	}{
+		{
+			name:   "default id key",
+			values: []any{"abc"},
+			want:   "id > 'abc'",
+			norm:   "id",
+		},
+		{
+			name:   "descending key with id tiebreaker",
+			sort:   "-created",
+			values: []any{"2024-01-01 10:00:00.000Z", "abc"},
+			want:   "(created < '2024-01-01 10:00:00.000Z' || (created = '2024-01-01 10:00:00.000Z' && id > 'abc'))",
+			norm:   "-created,id",
+		},
+		{
+			name:   "escaped values",
+			sort:   "+title, -score, id",
+			values: []any{"it's", float64(1.5), "abc"},
+			want:   `(title > 'it\'s' || (title = 'it\'s' && score < 1.5) || (title = 'it\'s' && score = 1.5 && id > 'abc'))`,
+			norm:   "title,-score,id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, sort, err := parseCursorSort(tt.sort)
+			require.NoError(t, err)
+			assert.Equal(t, tt.norm, sort)
+			got, err := keysetFilter(keys, tt.values)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	keys, _, err := parseCursorSort("title")
+	require.NoError(t, err)
+	_, err = keysetFilter(keys, []any{`a\`, "abc"})
+	assert.ErrorIs(t, err, filter.ErrInvalidValue)
+
+	_, _, err = parseCursorSort("expand.author.name")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestCursor_Encode(t *testing.T) {
+	keys, sort, err := parseCursorSort("-field")
+	require.NoError(t, err)
+
+	c, err := encodeCursor(map[string]any{"id": "abc", "field": "it's"}, keys, sort)
+	require.NoError(t, err)
+
+	values, err := decodeCursor(c, sort, len(keys))
+	require.NoError(t, err)
+	assert.Equal(t, []any{"it's", "abc"}, values)
+
+	_, err = decodeCursor(c, "id", 1)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = decodeCursor("not a cursor", sort, len(keys))
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = encodeCursor(map[string]any{"id": "abc"}, keys, sort)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
@@ -0,0 +1,154 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+)
+
+var (
+	ErrInvalidResponse = errors.New("invalid response")
+	ErrInvalidCursor   = errors.New("invalid cursor")
+	ErrMFARequired     = errors.New("mfa required")
+)
+
+type (
+	// ApiError is returned for every non 2xx response of the PocketBase API.
+	//
+	// It wraps ErrInvalidResponse, so the previous `errors.Is(err, ErrInvalidResponse)`
+	// checks keep working, and can be extracted with `errors.As`:
+	//
+	//	var apiErr *pocketbase.ApiError
+	//	if errors.As(err, &apiErr) {
+	//		log.Print(apiErr.Data["title"].Code)
+	//	}
+	ApiError struct { //nolint:revive // name requested by the SDK users, mirrors the PocketBase "ApiError"
+		Status  int                       `json:"status"`
+		Message string                    `json:"message"`
+		Data    map[string]ApiErrorDetail `json:"data"`
+		URL     string                    `json:"-"`
+		Method  string                    `json:"-"`
+
+		// Raw is the unparsed response body.
+		Raw string `json:"-"`
+	}
+
+	// ApiErrorDetail is a single field validation error from ApiError.Data.
+	ApiErrorDetail struct { //nolint:revive // keep in line with ApiError
+		Code    string         `json:"code"`
+		Message string         `json:"message"`
+		Params  map[string]any `json:"params,omitempty"`
+	}
+)
+
+// MFARequiredError is returned by the auth flows when the auth record requires
+// another authentication method, e.g. AuthWithOTP after AuthWithPassword.
+// Complete the authentication by passing the MFAID to the other method (AuthWithOTPMFA, AuthWithPasswordMFA).
+//
+// It matches ErrMFARequired and the 401 ApiError via errors.Is and errors.As:
+//
+//	var mfaErr *pocketbase.MFARequiredError
+//	if errors.As(err, &mfaErr) {
+//		_, err = users.AuthWithOTPMFA(otpID, password, mfaErr.MFAID)
+//	}
+type MFARequiredError struct {
+	MFAID string
+	*ApiError
+}
+
+func (e *MFARequiredError) Error() string {
+	return fmt.Sprintf("%v, mfaId: %s", ErrMFARequired, e.MFAID)
+}
+
+func (e *MFARequiredError) Unwrap() []error {
+	return []error{ErrMFARequired, e.ApiError}
+}
+
+// newAuthError returns the error of an auth flow response,
+// it's MFARequiredError if the response has an mfaId.
+func newAuthError(resp *resty.Response) error {
+	apiErr := newApiError(resp)
+	if apiErr.Status != http.StatusUnauthorized {
+		return apiErr
+	}
+	var body struct {
+		MFAID string `json:"mfaId"`
+	}
+	if err := json.Unmarshal(resp.Body(), &body); err != nil || body.MFAID == "" {
+		return apiErr
+	}
+	return &MFARequiredError{MFAID: body.MFAID, ApiError: apiErr}
+}
+
+func newApiError(resp *resty.Response) *ApiError {
+	e := &ApiError{
+		Status: resp.StatusCode(),
+		Raw:    resp.String(),
+	}
+	if resp.Request != nil {
+		e.URL = resp.Request.URL
+		e.Method = resp.Request.Method
+	}
+	e.parseBody(resp.Body())
+	return e
+}
+
+func (e *ApiError) parseBody(data []byte) {
+	var body struct {
+		Message string                     `json:"message"`
+		Data    map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return
+	}
+	e.Message = body.Message
+	for field, raw := range body.Data {
+		var detail ApiErrorDetail
+		// the data map is not always a validation errors map, skip anything else
+		if err := json.Unmarshal(raw, &detail); err != nil || detail.Code == "" {
+			continue
+		}
+		if e.Data == nil {
+			e.Data = make(map[string]ApiErrorDetail)
+		}
+		e.Data[field] = detail
+	}
+}
+
+func (e *ApiError) Error() string {
+	return fmt.Sprintf("pocketbase returned status: %d, msg: %s, err %v", e.Status, e.Raw, ErrInvalidResponse)
+}
+
+func (e *ApiError) Unwrap() error {
+	return ErrInvalidResponse
+}
+
+// IsNotFound reports whether err is an ApiError with 404 status.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsForbidden reports whether err is an ApiError with 403 status.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsUnauthorized reports whether err is an ApiError with 401 status.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsValidation reports whether err is an ApiError with 400 status
+// and at least one field validation error.
+func IsValidation(err error) bool {
+	var apiErr *ApiError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusBadRequest && len(apiErr.Data) > 0
+}
+
+func hasStatus(err error, status int) bool {
+	var apiErr *ApiError
+	return errors.As(err, &apiErr) && apiErr.Status == status
+}
@@ -0,0 +1,52 @@
+package pocketbase
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/pluja/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiError(t *testing.T) {
+	client := NewClient(defaultURL)
+
+	t.Run("validation error", func(t *testing.T) {
+		_, err := client.Create(migrations.PostsPublic, map[string]any{"id": "x"})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrInvalidResponse)
+		assert.True(t, IsValidation(err))
+		assert.False(t, IsNotFound(err))
+
+		var apiErr *ApiError
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+		assert.Equal(t, "Failed to create record.", apiErr.Message)
+		assert.Equal(t, http.MethodPost, apiErr.Method)
+		assert.Contains(t, apiErr.URL, "/api/collections/"+migrations.PostsPublic+"/records")
+		require.Contains(t, apiErr.Data, "id")
+		assert.Equal(t, "validation_min_text_constraint", apiErr.Data["id"].Code)
+		assert.EqualValues(t, 15, apiErr.Data["id"].Params["min"])
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := client.One(migrations.PostsPublic, "non_existing_id")
+		assert.True(t, IsNotFound(err))
+		assert.False(t, IsValidation(err))
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		_, err := client.Create(migrations.PostsAdmin, map[string]any{})
+		assert.True(t, IsForbidden(err))
+	})
+
+	t.Run("non api errors", func(t *testing.T) {
+		err := errors.New("some error")
+		assert.False(t, IsNotFound(err))
+		assert.False(t, IsForbidden(err))
+		assert.False(t, IsUnauthorized(err))
+		assert.False(t, IsValidation(err))
+	})
+}
@@ -0,0 +1,75 @@
+package pocketbase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pluja/pocketbase/filter"
+)
+
+type (
+	// ExternalAuths is the service of the OAuth2 identities linked to the auth records,
+	// i.e. the records of the "_externalAuths" system collection (PocketBase v0.23+).
+	//
+	// The auth records can manage their own external auths, superusers all of them.
+	ExternalAuths struct {
+		*Collection[ExternalAuth]
+	}
+
+	ExternalAuth struct {
+		ID            string `json:"id"`
+		CollectionRef string `json:"collectionRef"`
+		RecordRef     string `json:"recordRef"`
+		Provider      string `json:"provider"`
+		ProviderID    string `json:"providerId"`
+		Created       string `json:"created"`
+		Updated       string `json:"updated"`
+	}
+)
+
+// ListByRecord lists all the external auths linked to the auth record.
+func (s ExternalAuths) ListByRecord(collectionID string, recordID string) ([]ExternalAuth, error) {
+	return s.ListByRecordCtx(context.Background(), collectionID, recordID)
+}
+
+func (s ExternalAuths) ListByRecordCtx(ctx context.Context, collectionID string, recordID string) ([]ExternalAuth, error) {
+	return s.list(ctx, recordFilter(collectionID, recordID))
+}
+
+// Unlink unlinks the OAuth2 provider from the auth record.
+func (s ExternalAuths) Unlink(collectionID string, recordID string, provider string) error {
+	return s.UnlinkCtx(context.Background(), collectionID, recordID, provider)
+}
+
+func (s ExternalAuths) UnlinkCtx(ctx context.Context, collectionID string, recordID string, provider string) error {
+	auths, err := s.list(ctx, filter.And(recordFilter(collectionID, recordID), filter.Eq("provider", provider)))
+	if err != nil {
+		return err
+	}
+	if len(auths) == 0 {
+		return fmt.Errorf("[external-auths] the provider %q isn't linked to the record %s", provider, recordID)
+	}
+	for _, auth := range auths {
+		if err := s.DeleteCtx(ctx, auth.ID); err != nil {
+			return fmt.Errorf("[external-auths] can't unlink the provider %q, err %w", provider, err)
+		}
+	}
+	return nil
+}
+
+func (s ExternalAuths) list(ctx context.Context, f filter.Expr) ([]ExternalAuth, error) {
+	filters, err := f.Build()
+	if err != nil {
+		return nil, fmt.Errorf("[external-auths] can't list external auths, err %w", err)
+	}
+	resp, err := s.FullListCtx(ctx, ParamsList{Filters: filters, Sort: "created"})
+	if err != nil {
+		return nil, fmt.Errorf("[external-auths] can't list external auths, err %w", err)
+	}
+	return resp.Items, nil
+}
+
+// recordFilter matches the records of the system collections referencing the auth record.
+func recordFilter(collectionID string, recordID string) filter.Expr {
+	return filter.And(filter.Eq("collectionRef", collectionID), filter.Eq("recordRef", recordID))
+}
@@ -0,0 +1,47 @@
+package pocketbase
+
+import (
+	"testing"
+
+	"github.com/pluja/pocketbase/migrations"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalAuths(t *testing.T) {
+	email := testAuthRecord(t, migrations.UsersOAuth2)
+	client := NewClient(defaultURL)
+	auth, err := CollectionSet[Record](client, migrations.UsersOAuth2).AuthWithPassword(email, email)
+	require.NoError(t, err)
+	collectionID, recordID := auth.Record.CollectionID, auth.Record.ID
+
+	// linked by the OAuth2 flow
+	admin := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	_, err = admin.Create(core.CollectionNameExternalAuths, map[string]any{
+		"collectionRef": collectionID,
+		"recordRef":     recordID,
+		"provider":      "oidc",
+		"providerId":    recordID,
+	})
+	require.NoError(t, err)
+
+	auths, err := client.ExternalAuths().ListByRecord(collectionID, recordID)
+	require.NoError(t, err)
+	require.Len(t, auths, 1)
+	assert.Equal(t, "oidc", auths[0].Provider)
+	assert.Equal(t, recordID, auths[0].ProviderID)
+	assert.Equal(t, recordID, auths[0].RecordRef)
+
+	// the other records' external auths aren't visible
+	auths, err = NewClient(defaultURL).ExternalAuths().ListByRecord(collectionID, recordID)
+	require.NoError(t, err)
+	assert.Empty(t, auths)
+
+	require.NoError(t, client.ExternalAuths().Unlink(collectionID, recordID, "oidc"))
+	auths, err = admin.ExternalAuths().ListByRecord(collectionID, recordID)
+	require.NoError(t, err)
+	assert.Empty(t, auths)
+
+	assert.Error(t, client.ExternalAuths().Unlink(collectionID, recordID, "oidc"))
+}
@@ -1,6 +1,7 @@
 package pocketbase
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -17,11 +18,16 @@ type (
 
 // GetToken requests a new private file access token for the current auth model (admin or record).
 func (f Files) GetToken() (string, error) {
-	if err := f.Authorize(); err != nil {
+	return f.GetTokenCtx(context.Background())
+}
+
+func (f Files) GetTokenCtx(ctx context.Context) (string, error) {
+	if err := f.AuthorizeCtx(ctx); err != nil {
 		return "", err
 	}
 
 	request := f.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json")
 
 	resp, err := request.Post(f.url + "/api/files/token")
@@ -30,11 +36,7 @@ func (f Files) GetToken() (string, error) {
 	}
 
 	if resp.IsError() {
-		return "", fmt.Errorf("[files] pocketbase returned status at getting a new token: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return "", fmt.Errorf("[files] getting a new token: %w", newApiError(resp))
 	}
 
 	response := ResponseGetToken{}
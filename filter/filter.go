@@ -0,0 +1,268 @@
+// Package filter builds PocketBase filter expressions with safely quoted and escaped values.
+//
+//	f, err := filter.And(
+//		filter.Eq("author", filter.Request("auth.id")),
+//		filter.Like("title", userInput),
+//		filter.In("status", "draft", "published"),
+//	).Build()
+//
+// Field names and references are written to the expression as they are,
+// only the values are escaped, so never build them from user input.
+//
+// A text value ending with a backslash can't be quoted (the backslash would escape the closing quote),
+// such values are rejected with ErrInvalidValue.
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is the layout of the date values, the same as used by PocketBase.
+const DateLayout = "2006-01-02 15:04:05.000Z"
+
+// ErrInvalidValue is returned for the values that can't be quoted, i.e. the text ending with a backslash.
+var ErrInvalidValue = errors.New("filter: the value can't end with a backslash")
+
+// invalidExpr is the String() of an expression with an invalid value,
+// it fails to parse, so PocketBase rejects the filter if the error is ignored.
+const invalidExpr = "`invalid filter value`"
+
+type (
+	// Expr is a filter expression, use Build() to get it for ParamsList.Filters.
+	// The zero value is the empty expression.
+	Expr struct {
+		expr string
+		err  error
+	}
+
+	// Ref is a field or placeholder reference, e.g. "@request.auth.id",
+	// it's used as a value without quoting.
+	Ref string
+)
+
+var placeholderRegex = regexp.MustCompile(`\{:(\w+)\}`)
+
+// Raw returns the expression as it is, e.g. to combine a filter written by hand with the built ones.
+// It isn't parenthesized, so wrap an expression with "||" in parentheses before joining it with And.
+func Raw(expr string) Expr {
+	return Expr{expr: expr}
+}
+
+// Build returns the expression or the error of an invalid value.
+func (e Expr) Build() (string, error) {
+	if e.err != nil {
+		return "", e.err
+	}
+	return e.expr, nil
+}
+
+// String returns the expression, an expression with an invalid value is returned
+// as an unparsable filter, so it never matches more than intended.
+func (e Expr) String() string {
+	if e.err != nil {
+		return invalidExpr
+	}
+	return e.expr
+}
+
+func (r Ref) String() string {
+	return string(r)
+}
+
+// Field returns a reference to another field of the record, e.g. Gt("updated", Field("created")).
+func Field(name string) Ref {
+	return Ref(name)
+}
+
+// Request returns a reference to the request info, e.g. Request("auth.id") or Request("body.title").
+func Request(path string) Ref {
+	return Ref("@request." + path)
+}
+
+// Collection returns a reference to a field of another collection,
+// e.g. Collection("members", "user") for "@collection.members.user".
+func Collection(name string, path string) Ref {
+	return Ref("@collection." + name + "." + path)
+}
+
+// Eq returns the "field = value" expression.
+func Eq(field string, value any) Expr { return compare(field, "=", value) }
+
+// Neq returns the "field != value" expression.
+func Neq(field string, value any) Expr { return compare(field, "!=", value) }
+
+// Gt returns the "field > value" expression.
+func Gt(field string, value any) Expr { return compare(field, ">", value) }
+
+// Gte returns the "field >= value" expression.
+func Gte(field string, value any) Expr { return compare(field, ">=", value) }
+
+// Lt returns the "field < value" expression.
+func Lt(field string, value any) Expr { return compare(field, "<", value) }
+
+// Lte returns the "field <= value" expression.
+func Lte(field string, value any) Expr { return compare(field, "<=", value) }
+
+// Like returns the "field ~ value" expression. The value is wrapped
+// with "%" by PocketBase unless it already contains one.
+func Like(field string, value any) Expr { return compare(field, "~", value) }
+
+// NotLike returns the "field !~ value" expression.
+func NotLike(field string, value any) Expr { return compare(field, "!~", value) }
+
+// AnyEq returns the "field ?= value" expression, matching if any of the
+// multiple values (e.g. of a multiple relation or select field) equals the value.
+func AnyEq(field string, value any) Expr { return compare(field, "?=", value) }
+
+// AnyNeq returns the "field ?!= value" expression.
+func AnyNeq(field string, value any) Expr { return compare(field, "?!=", value) }
+
+// AnyGt returns the "field ?> value" expression.
+func AnyGt(field string, value any) Expr { return compare(field, "?>", value) }
+
+// AnyGte returns the "field ?>= value" expression.
+func AnyGte(field string, value any) Expr { return compare(field, "?>=", value) }
+
+// AnyLt returns the "field ?< value" expression.
+func AnyLt(field string, value any) Expr { return compare(field, "?<", value) }
+
+// AnyLte returns the "field ?<= value" expression.
+func AnyLte(field string, value any) Expr { return compare(field, "?<=", value) }
+
+// AnyLike returns the "field ?~ value" expression.
+func AnyLike(field string, value any) Expr { return compare(field, "?~", value) }
+
+// AnyNotLike returns the "field ?!~ value" expression.
+func AnyNotLike(field string, value any) Expr { return compare(field, "?!~", value) }
+
+// In matches the records whose field equals any of the values.
+// It never matches when no values are provided.
+func In(field string, values ...any) Expr {
+	if len(values) == 0 {
+		return Raw("1 = 0")
+	}
+	exprs := make([]Expr, 0, len(values))
+	for _, v := range values {
+		exprs = append(exprs, Eq(field, v))
+	}
+	return Or(exprs...)
+}
+
+// And joins the expressions with "&&", empty expressions are skipped.
+func And(exprs ...Expr) Expr {
+	return join(" && ", exprs)
+}
+
+// Or joins the expressions with "||", empty expressions are skipped.
+func Or(exprs ...Expr) Expr {
+	return join(" || ", exprs)
+}
+
+// Filter replaces the {:name} placeholders in expr with the quoted and escaped params,
+// the same as the `pb.filter()` of the JS SDK:
+//
+//	filter.Filter("title ~ {:title} && created >= {:created}", map[string]any{
+//		"title":   "it's",
+//		"created": time.Now(),
+//	})
+//
+// Placeholders without a param are left as they are.
+func Filter(expr string, params map[string]any) (string, error) {
+	if len(params) == 0 {
+		return expr, nil
+	}
+	var err error
+	result := placeholderRegex.ReplaceAllStringFunc(expr, func(match string) string {
+		v, ok := params[match[2:len(match)-1]]
+		if !ok {
+			return match
+		}
+		value, valueErr := Value(v)
+		if err == nil {
+			err = valueErr
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// Value formats v as a filter literal:
+// strings, dates, fmt.Stringer and other types (as JSON) are quoted and escaped,
+// numbers and booleans are written as they are, nil is "null" and Ref isn't quoted.
+func Value(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "null", nil
+	case Ref:
+		return string(v), nil
+	case string:
+		return quote(v)
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case time.Time:
+		return quote(v.UTC().Format(DateLayout))
+	case fmt.Stringer:
+		return quote(v.String())
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return quote(fmt.Sprint(v))
+		}
+		return quote(string(data))
+	}
+}
+
+// quote wraps s in single quotes. The filter scanner ends the text at the first quote
+// without a backslash before it and unescapes only the quotes, so a backslash
+// followed by a quote needs no special handling, but a trailing one can't be quoted at all.
+func quote(s string) (string, error) {
+	if strings.HasSuffix(s, `\`) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidValue, s)
+	}
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'", nil
+}
+
+func compare(field string, op string, value any) Expr {
+	v, err := Value(value)
+	if err != nil {
+		return Expr{err: fmt.Errorf("%s: %w", field, err)}
+	}
+	return Expr{expr: field + " " + op + " " + v}
+}
+
+func join(sep string, exprs []Expr) Expr {
+	parts := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		if e.err != nil {
+			return e
+		}
+		if e.expr != "" {
+			parts = append(parts, e.expr)
+		}
+	}
+	switch len(parts) {
+	case 0:
+		return Expr{}
+	case 1:
+		return Expr{expr: parts[0]}
+	default:
+		return Expr{expr: "(" + strings.Join(parts, sep) + ")"}
+	}
+}
@@ -0,0 +1,139 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ganigeorgiev/fexpr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stringer struct{}
+
+func (stringer) String() string { return "it's a stringer" }
+
+func TestValue(t *testing.T) {
+	date := time.Date(2024, 1, 2, 15, 4, 5, 123000000, time.FixedZone("CET", 3600))
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"nil", nil, "null"},
+		{"string", "abc", "'abc'"},
+		{"escaped string", `it's' || id != '`, `'it\'s\' || id != \''`},
+		{"backslash", `a\b`, `'a\b'`},
+		{"backslash before quote", `a\'b`, `'a\\'b'`},
+		{"bool", true, "true"},
+		{"int", 42, "42"},
+		{"int64", int64(-42), "-42"},
+		{"float", 1.5, "1.5"},
+		{"date", date, "'2024-01-02 14:04:05.123Z'"},
+		{"stringer", stringer{}, `'it\'s a stringer'`},
+		{"json", map[string]any{"a": "b"}, `'{"a":"b"}'`},
+		{"reference", Request("auth.id"), "@request.auth.id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Value(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValue_Backslash(t *testing.T) {
+	// the quoted value must be parsed by PocketBase as a single text token of the same value
+	for _, value := range []string{`a\b`, `\'`, `a\'b`, `'\'`, `\\'`, `// x`} {
+		quoted, err := Value(value)
+		require.NoError(t, err)
+		groups, err := fexpr.Parse("a = " + quoted)
+		require.NoError(t, err, quoted)
+		require.Len(t, groups, 1)
+		expr := groups[0].Item.(fexpr.Expr)
+		assert.Equal(t, fexpr.TokenText, expr.Right.Type, quoted)
+		assert.Equal(t, value, expr.Right.Literal, quoted)
+	}
+
+	for _, value := range []any{`\`, `a\`, `a\\`, textStringer(`b\`)} {
+		_, err := Value(value)
+		assert.ErrorIs(t, err, ErrInvalidValue)
+	}
+}
+
+type textStringer string
+
+func (s textStringer) String() string { return string(s) }
+
+func TestExpr(t *testing.T) {
+	tests := []struct {
+		name string
+		expr Expr
+		want string
+	}{
+		{"eq", Eq("title", "a'b"), `title = 'a\'b'`},
+		{"raw", And(Raw("(a = 1 || b = 2)"), Eq("c", 3)), "((a = 1 || b = 2) && c = 3)"},
+		{"neq", Neq("count", 1), "count != 1"},
+		{"comparisons", And(Gt("a", 1), Gte("b", 2), Lt("c", 3), Lte("d", 4)), "(a > 1 && b >= 2 && c < 3 && d <= 4)"},
+		{"like", Or(Like("title", "abc"), NotLike("title", "%d")), "(title ~ 'abc' || title !~ '%d')"},
+		{"any of", And(AnyEq("tags", "go"), AnyNeq("tags", "js"), AnyLike("tags", "x")), "(tags ?= 'go' && tags ?!= 'js' && tags ?~ 'x')"},
+		{"any of comparisons", And(AnyGt("a", 1), AnyGte("a", 1), AnyLt("a", 1), AnyLte("a", 1), AnyNotLike("a", "b")), "(a ?> 1 && a ?>= 1 && a ?< 1 && a ?<= 1 && a ?!~ 'b')"},
+		{"in", In("status", "draft", "published"), "(status = 'draft' || status = 'published')"},
+		{"in single", In("status", "draft"), "status = 'draft'"},
+		{"in empty", In("status"), "1 = 0"},
+		{"nested", And(Eq("a", true), Or(Eq("b", nil), Eq("c", 1.5))), "(a = true && (b = null || c = 1.5))"},
+		{"empty skipped", And(Expr{}, Eq("a", 1), Or()), "a = 1"},
+		{"empty", And(), ""},
+		{"request", Eq("author", Request("auth.id")), "author = @request.auth.id"},
+		{"collection", Eq(Request("auth.id").String(), Collection("members", "user")), "@request.auth.id = @collection.members.user"},
+		{"field", Gt("updated", Field("created")), "updated > created"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.expr.Build()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want, tt.expr.String())
+		})
+	}
+}
+
+func TestExpr_InvalidValue(t *testing.T) {
+	expr := And(Eq("owner", `\`), Or(Eq("title", ` || id != "" //`), Eq("a", 1)))
+
+	_, err := expr.Build()
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.ErrorContains(t, err, "owner")
+
+	// the ignored error doesn't widen the filter
+	_, err = fexpr.Parse(expr.String())
+	assert.Error(t, err)
+}
+
+func TestFilter(t *testing.T) {
+	got, err := Filter("title ~ {:title} && active = {:active} && created >= {:created} && x = {:missing}", map[string]any{
+		"title":   "it's",
+		"active":  false,
+		"created": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `title ~ 'it\'s' && active = false && created >= '2024-01-02 00:00:00.000Z' && x = {:missing}`, got)
+	got, err = Filter("a = {:a}", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "a = {:a}", got)
+}
+
+func TestFilter_Injection(t *testing.T) {
+	_, err := Filter("owner = {:o} && title = {:t}", map[string]any{"o": `\`, "t": ` || id != "" //`})
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	got, err := Filter("owner = {:o} && title = {:t}", map[string]any{"o": `\'`, "t": ` || id != "" //`})
+	require.NoError(t, err)
+	groups, err := fexpr.Parse(got)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, `\'`, groups[0].Item.(fexpr.Expr).Right.Literal)
+	assert.Equal(t, ` || id != "" //`, groups[1].Item.(fexpr.Expr).Right.Literal)
+}
@@ -0,0 +1,138 @@
+package pocketbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/donovanhide/eventsource"
+	"github.com/pluja/pocketbase/filter"
+)
+
+// gapRecovery remembers the last seen record change of a stream
+// to list the changes missed while reconnecting.
+type gapRecovery struct {
+	mu       sync.Mutex
+	lastSeen string
+}
+
+func newGapRecovery() *gapRecovery {
+	return &gapRecovery{lastSeen: time.Now().UTC().Format(filter.DateLayout)}
+}
+
+func (g *gapRecovery) since() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.lastSeen
+}
+
+func (g *gapRecovery) seen(updated string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if updated > g.lastSeen {
+		g.lastSeen = updated
+	}
+}
+
+func (g *gapRecovery) seenEvent(ev eventsource.Event) {
+	var data struct {
+		Record struct {
+			Updated string `json:"updated"`
+		} `json:"record"`
+	}
+	if err := decodeEventData(ev, &data); err == nil {
+		g.seen(data.Record.Updated)
+	}
+}
+
+// recoverGap lists the records changed since the last seen change and pushes them
+// to the stream as synthetic "create" or "update" events, ordered by the change time.
+//
+// It's called after the topics are subscribed again and before the connection
+// reader is resumed, so the synthetic events precede the live ones.
+func (c *Collection[T]) recoverGap(ctx context.Context, stream *eventStream[Event[T]], g *gapRecovery, opts SubscribeOptions, targets []string) {
+	_, err := c.pushChanges(ctx, stream, g, opts, targets)
+	if err != nil && !errors.Is(err, ErrStreamOverflow) {
+		stream.queue.push(Event[T]{Error: fmt.Errorf("[realtime] can't recover the missed events, err %w", err)})
+	}
+}
+
+// pushChanges lists the records changed since the last seen change and pushes them
+// to the stream as "create" or "update" events, it returns the IDs of the pushed records.
+// The stream is closed with ErrStreamOverflow if its buffer overflows with the OverflowClose policy.
+func (c *Collection[T]) pushChanges(ctx context.Context, stream *eventStream[Event[T]], g *gapRecovery, opts SubscribeOptions, targets []string) ([]string, error) {
+	since := g.since()
+	fields := opts.Fields
+	if fields != "" {
+		fields += ",id,created,updated" // required to track the changes
+	}
+	filters, err := filter.And(
+		filter.Gt("updated", since),
+		targetsFilter(targets),
+		parenthesize(opts.Filter),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+	params := ParamsList{
+		Sort:    "updated,id",
+		Expand:  opts.Expand,
+		Fields:  fields,
+		Filters: filters,
+	}
+
+	records, err := c.Client.FullListCtx(ctx, c.Name, params)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(records.Items))
+	for _, record := range records.Items {
+		e := Event[T]{Action: "update"}
+		if created, _ := record["created"].(string); created > since {
+			e.Action = "create"
+		}
+		if updated, _ := record["updated"].(string); updated != "" {
+			g.seen(updated)
+		}
+		if id, _ := record["id"].(string); id != "" {
+			ids = append(ids, id)
+		}
+		data, err := json.Marshal(record)
+		if err == nil {
+			err = json.Unmarshal(data, &e.Record)
+		}
+		e.Error = err
+		if !stream.queue.push(e) {
+			stream.close(ErrStreamOverflow)
+			return ids, ErrStreamOverflow
+		}
+	}
+	return ids, nil
+}
+
+// targetsFilter limits the records to the subscribed record topics, e.g. "posts/RECORD_ID".
+// It returns an empty expression if any of the topics is the whole collection.
+func targetsFilter(targets []string) filter.Expr {
+	ids := make([]any, 0, len(targets))
+	for _, t := range targets {
+		t, _, _ = strings.Cut(t, "?")
+		_, id, found := strings.Cut(t, "/")
+		if !found || id == "*" {
+			return filter.Expr{}
+		}
+		ids = append(ids, id)
+	}
+	return filter.In("id", ids...)
+}
+
+func parenthesize(expr string) filter.Expr {
+	if expr == "" {
+		return filter.Expr{}
+	}
+	return filter.Raw("(" + expr + ")")
+}
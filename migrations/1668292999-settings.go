@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		settings := app.Settings()
+		settings.Batch.Enabled = true
+		settings.Batch.MaxRequests = 50
+		settings.Batch.Timeout = 3
+
+		return app.Save(settings)
+	}, func(_ core.App) error {
+		return nil
+	})
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection := core.NewBaseCollection(PostsFiles)
+		collection.ListRule = types.Pointer("")
+		collection.ViewRule = types.Pointer("")
+		collection.CreateRule = types.Pointer("")
+		collection.UpdateRule = types.Pointer("")
+		collection.DeleteRule = types.Pointer("")
+		collection.Fields.Add(
+			&core.TextField{Name: "field"},
+			&core.FileField{Name: "files", MaxSelect: 2, MaxSize: 1 << 20},
+		)
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId(PostsFiles)
+		if err != nil {
+			return err
+		}
+		return app.Delete(collection)
+	})
+}
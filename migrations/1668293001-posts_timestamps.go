@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection := core.NewBaseCollection(PostsTimestamps)
+		collection.ListRule = types.Pointer("")
+		collection.ViewRule = types.Pointer("")
+		collection.CreateRule = types.Pointer("")
+		collection.UpdateRule = types.Pointer("")
+		collection.DeleteRule = types.Pointer("")
+		collection.Fields.Add(
+			&core.TextField{Name: "field"},
+			&core.AutodateField{Name: "created", OnCreate: true},
+			&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true},
+		)
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId(PostsTimestamps)
+		if err != nil {
+			return err
+		}
+		return app.Delete(collection)
+	})
+}
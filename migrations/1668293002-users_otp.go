@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection := core.NewAuthCollection(UsersOTP)
+		collection.OTP.Enabled = true
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+
+		r := core.NewRecord(collection)
+		r.SetEmail(OTPEmailPassword)
+		r.SetVerified(true)
+		r.SetPassword(OTPEmailPassword)
+		return app.Save(r)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId(UsersOTP)
+		if err != nil {
+			return err
+		}
+		return app.Delete(collection)
+	})
+}
@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection := core.NewAuthCollection(UsersMFA)
+		collection.OTP.Enabled = true
+		collection.MFA.Enabled = true
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId(UsersMFA)
+		if err != nil {
+			return err
+		}
+		return app.Delete(collection)
+	})
+}
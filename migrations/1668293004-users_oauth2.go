@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection := core.NewAuthCollection(UsersOAuth2)
+		collection.CreateRule = types.Pointer("") // the new OAuth2 users
+		collection.OAuth2.Enabled = true
+		collection.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+			Name:         "oidc",
+			ClientId:     "client",
+			ClientSecret: "secret",
+			AuthURL:      OAuth2ProviderURL + "/auth",
+			TokenURL:     OAuth2ProviderURL + "/token",
+			UserInfoURL:  OAuth2ProviderURL + "/userinfo",
+			DisplayName:  "Test provider",
+		}}
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId(UsersOAuth2)
+		if err != nil {
+			return err
+		}
+		return app.Delete(collection)
+	})
+}
@@ -5,6 +5,14 @@ const (
 	PostsAdmin         = "posts_admin"
 	PostsUser          = "posts_user"
 	PostsPublic        = "posts_public"
+	PostsFiles         = "posts_files"
+	PostsTimestamps    = "posts_timestamps"
+	UsersOTP           = "users_otp"
+	UsersMFA           = "users_mfa"
+	UsersOAuth2        = "users_oauth2"
 	AdminEmailPassword = "admin@admin.com"
 	UserEmailPassword  = "user@user.com"
+	OTPEmailPassword   = "otp@user.com"
+	// OAuth2ProviderURL is the fake OIDC provider started by the OAuth2 tests.
+	OAuth2ProviderURL = "http://127.0.0.1:8092"
 )
@@ -0,0 +1,134 @@
+package pocketbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// OAuth2Options configures the AuthWithOAuth2 flow.
+type OAuth2Options struct {
+	// ListenAddr is the loopback address of the redirect server, the redirect URL is "http://<ListenAddr>/".
+	// Set a fixed port (e.g. "127.0.0.1:8765") if the provider accepts only registered redirect URLs,
+	// a random port is used if empty.
+	ListenAddr string
+
+	// OpenURL is called with the provider's auth URL, e.g. to open it in the browser.
+	// The URL is printed to stderr if nil.
+	OpenURL func(authURL string) error
+}
+
+type oauth2Redirect struct {
+	code string
+	err  error
+}
+
+// AuthWithOAuth2 authenticate a single auth collection record with the OAuth2 provider,
+// e.g. from a CLI app. The redirect of the provider is received by a local loopback server:
+//   - the provider's auth URL (from ListAuthMethods) is opened via opts.OpenURL
+//   - the redirect state is validated (the redirects with another state are ignored)
+//     and the code is exchanged with the PKCE code verifier
+//   - the flow is canceled with ctx, so give it a timeout
+//
+// On success, this method also automatically updates the client's AuthStore data,
+// like AuthWithOAuth2Code.
+func (c *Collection[T]) AuthWithOAuth2(ctx context.Context, provider string, opts OAuth2Options) (AuthWithOauth2Response[T], error) {
+	var response AuthWithOauth2Response[T]
+	methods, err := c.ListAuthMethodsCtx(ctx)
+	if err != nil {
+		return response, err
+	}
+	var info *providerInfo
+	for i := range methods.OAuth2.Providers {
+		if methods.OAuth2.Providers[i].Name == provider {
+			info = &methods.OAuth2.Providers[i]
+			break
+		}
+	}
+	if info == nil {
+		return response, fmt.Errorf("[records] auth-with-oauth2: the provider %q isn't enabled", provider)
+	}
+
+	addr := opts.ListenAddr
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+	ln, err := (&net.ListenConfig{}).Listen(ctx, "tcp", addr)
+	if err != nil {
+		return response, fmt.Errorf("[records] can't listen for the oauth2 redirect, err %w", err)
+	}
+	redirectURL := "http://" + ln.Addr().String() + "/"
+
+	redirects := make(chan oauth2Redirect, 1)
+	server := &http.Server{
+		Handler:           oauth2RedirectHandler(info.State, redirects),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = server.Serve(ln)
+	}()
+	defer server.Close()
+
+	openURL := opts.OpenURL
+	if openURL == nil {
+		openURL = printAuthURL
+	}
+	if err := openURL(info.AuthURL + url.QueryEscape(redirectURL)); err != nil {
+		return response, fmt.Errorf("[records] can't open the oauth2 auth url, err %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return response, ctx.Err()
+	case r := <-redirects:
+		if r.err != nil {
+			return response, fmt.Errorf("[records] auth-with-oauth2: %w", r.err)
+		}
+		return c.AuthWithOAuth2CodeCtx(ctx, provider, r.code, info.CodeVerifier, redirectURL)
+	}
+}
+
+// oauth2RedirectHandler sends the code (or the error) of the first redirect to the redirects channel.
+// A redirect with another state isn't a response to our auth URL (e.g. a forged one),
+// it's answered with 400 and the flow keeps waiting for the right one.
+func oauth2RedirectHandler(state string, redirects chan<- oauth2Redirect) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/{$}", func(w http.ResponseWriter, r *http.Request) {
+		// the form_post providers (e.g. Apple) send the params in the body
+		if r.FormValue("state") != state {
+			http.Error(w, "Invalid OAuth2 state.", http.StatusBadRequest)
+			return
+		}
+
+		var redirect oauth2Redirect
+		switch {
+		case r.FormValue("error") != "":
+			redirect.err = fmt.Errorf("the provider returned %s: %s", r.FormValue("error"), r.FormValue("error_description"))
+		case r.FormValue("code") == "":
+			redirect.err = errors.New("missing oauth2 code")
+		default:
+			redirect.code = r.FormValue("code")
+		}
+
+		select {
+		case redirects <- redirect:
+		default: // the flow is already completed
+		}
+		if redirect.err != nil {
+			http.Error(w, "Authentication failed, please try again.", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte("Authentication completed, you can close this window."))
+	})
+	return mux
+}
+
+func printAuthURL(authURL string) error {
+	_, err := fmt.Fprintf(os.Stderr, "Open the following URL in your browser to authenticate:\n%s\n", authURL)
+	return err
+}
@@ -0,0 +1,176 @@
+package pocketbase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pluja/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startOAuth2Provider starts the fake OIDC provider configured for the migrations.UsersOAuth2 collection.
+// It redirects every auth request right away and verifies the PKCE code verifier.
+func startOAuth2Provider(t *testing.T) {
+	var mu sync.Mutex
+	challenges := map[string]string{} // code -> code challenge
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /auth", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		code := "code_" + q.Get("state")
+		mu.Lock()
+		challenges[code] = q.Get("code_challenge")
+		mu.Unlock()
+
+		redirect, err := url.Parse(q.Get("redirect_uri"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		redirect.RawQuery = url.Values{"code": {code}, "state": {q.Get("state")}}.Encode()
+		http.Redirect(w, r, redirect.String(), http.StatusFound)
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		challenge := challenges[r.FormValue("code")]
+		mu.Unlock()
+		sum := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if challenge == "" || base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"access_token":"access_token","token_type":"Bearer","expires_in":3600}`)
+	})
+	mux.HandleFunc("GET /userinfo", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"sub":            "oauth2_user",
+			"email":          "oauth2@user.com",
+			"email_verified": true,
+			"name":           "OAuth2 User",
+		})
+	})
+
+	ln, err := net.Listen("tcp", strings.TrimPrefix(migrations.OAuth2ProviderURL, "http://"))
+	require.NoError(t, err)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: time.Second}
+	go func() {
+		_ = server.Serve(ln)
+	}()
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+}
+
+func TestCollection_AuthWithOauth2(t *testing.T) {
+	startOAuth2Provider(t)
+
+	t.Run("authenticate via the loopback redirect", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		client := NewClient(defaultURL)
+
+		response, err := CollectionSet[Record](client, migrations.UsersOAuth2).AuthWithOAuth2(ctx, "oidc", OAuth2Options{
+			OpenURL: func(authURL string) error {
+				// the browser of the user
+				resp, err := http.Get(authURL) //nolint:noctx // test only
+				if err != nil {
+					return err
+				}
+				defer resp.Body.Close()
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				return nil
+			},
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.Token)
+		assert.Equal(t, "oauth2@user.com", response.Record.Email)
+		assert.Equal(t, "oauth2_user", response.Meta.ID)
+		assert.Equal(t, "access_token", response.Meta.AccessToken)
+		assert.Equal(t, "OAuth2 User", response.Meta.RawUser["name"])
+		assert.Equal(t, response.Token, client.AuthStore().Token())
+		assert.Contains(t, string(client.AuthRecord()), "oauth2@user.com")
+	})
+
+	t.Run("forged state", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		response, err := CollectionSet[User](NewClient(defaultURL), migrations.UsersOAuth2).AuthWithOAuth2(ctx, "oidc", OAuth2Options{
+			OpenURL: func(authURL string) error {
+				u, err := url.Parse(authURL)
+				if err != nil {
+					return err
+				}
+				redirect := u.Query().Get("redirect_uri") + "?" + url.Values{"code": {"code"}, "state": {"forged"}}.Encode()
+				resp, err := http.Get(redirect) //nolint:noctx // test only
+				if err != nil {
+					return err
+				}
+				resp.Body.Close()
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+				// the flow still waits for the redirect of the provider
+				resp, err = http.Get(authURL) //nolint:noctx // test only
+				if err != nil {
+					return err
+				}
+				resp.Body.Close()
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				return nil
+			},
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.Token)
+	})
+
+	t.Run("forged state only", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+
+		_, err := CollectionSet[User](NewClient(defaultURL), migrations.UsersOAuth2).AuthWithOAuth2(ctx, "oidc", OAuth2Options{
+			OpenURL: func(authURL string) error {
+				u, err := url.Parse(authURL)
+				if err != nil {
+					return err
+				}
+				redirect := u.Query().Get("redirect_uri") + "?" + url.Values{"code": {"code"}, "state": {"forged"}}.Encode()
+				resp, err := http.Get(redirect) //nolint:noctx // test only
+				if err != nil {
+					return err
+				}
+				defer resp.Body.Close()
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				return nil
+			},
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("no redirect", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := CollectionSet[User](NewClient(defaultURL), migrations.UsersOAuth2).AuthWithOAuth2(ctx, "oidc", OAuth2Options{
+			OpenURL: func(string) error { return nil },
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("disabled provider", func(t *testing.T) {
+		_, err := CollectionSet[User](NewClient(defaultURL), migrations.UsersOAuth2).AuthWithOAuth2(context.Background(), "github", OAuth2Options{})
+		assert.ErrorContains(t, err, "isn't enabled")
+	})
+}
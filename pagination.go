@@ -0,0 +1,128 @@
+package pocketbase
+
+import (
+	"context"
+	"iter"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// listFunc fetches a single page of records.
+type listFunc[T any] func(ctx context.Context, params ParamsList) (ResponseList[T], error)
+
+// fullList fetches all pages starting from the first one and merges them into a single response.
+func fullList[T any](ctx context.Context, params ParamsList, list listFunc[T]) (ResponseList[T], error) {
+	var response ResponseList[T]
+	params.Page = 1
+	if params.Size <= 0 {
+		params.Size = defaultPageSize
+	}
+
+	r, err := list(ctx, params)
+	if err != nil {
+		return response, err
+	}
+	response.Items = append(response.Items, r.Items...)
+	response.Page = r.Page
+	response.PerPage = r.PerPage
+	response.TotalItems = r.TotalItems
+	response.TotalPages = r.TotalPages
+
+	if params.Concurrency > 1 && !params.SkipTotal && r.TotalPages > 1 {
+		items, err := fetchPages(ctx, params, 2, r.TotalPages, list)
+		if err != nil {
+			return response, err
+		}
+		response.Items = append(response.Items, items...)
+		return response, nil
+	}
+
+	for hasNextPage(r, params) {
+		params.Page++
+		r, err = list(ctx, params)
+		if err != nil {
+			return response, err
+		}
+		response.Items = append(response.Items, r.Items...)
+	}
+
+	return response, nil
+}
+
+// fetchPages fetches the pages from..to (inclusive) with at most params.Concurrency
+// requests in flight and returns their records in the page order.
+// The first error cancels all the remaining requests.
+func fetchPages[T any](ctx context.Context, params ParamsList, from, to int, list listFunc[T]) ([]T, error) {
+	pages := make([][]T, to-from+1)
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(params.Concurrency)
+	for page := from; page <= to; page++ {
+		if ctx.Err() != nil {
+			break // a previous page failed, don't start new requests
+		}
+		g.Go(func() error {
+			p := params
+			p.Page = page
+			r, err := list(ctx, p)
+			if err != nil {
+				return err
+			}
+			pages[page-from] = r.Items
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var items []T
+	for _, page := range pages {
+		items = append(items, page...)
+	}
+	return items, nil
+}
+
+// paginate lazily fetches the pages on demand and yields the records one by one.
+// The iteration stops on the first error, which is yielded with a zero record.
+func paginate[T any](ctx context.Context, params ParamsList, list listFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if params.Page <= 0 {
+			params.Page = 1
+		}
+		if params.Size <= 0 {
+			params.Size = defaultPageSize
+		}
+
+		for {
+			r, err := list(ctx, params)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range r.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !hasNextPage(r, params) {
+				return
+			}
+			params.Page++
+		}
+	}
+}
+
+// hasNextPage reports whether there is another page after r.
+// When the total counts are skipped, a full page means there might be more records.
+func hasNextPage[T any](r ResponseList[T], params ParamsList) bool {
+	if r.TotalPages >= 0 && !params.SkipTotal {
+		return params.Page < r.TotalPages
+	}
+	perPage := r.PerPage
+	if perPage <= 0 {
+		perPage = params.Size
+	}
+	return len(r.Items) > 0 && len(r.Items) >= perPage
+}
@@ -0,0 +1,73 @@
+package pocketbase
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFullList_Concurrency(t *testing.T) {
+	const totalPages = 10
+
+	t.Run("pages are merged in order", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int32
+		list := func(ctx context.Context, params ParamsList) (ResponseList[int], error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			// later pages respond faster
+			time.Sleep(time.Duration(totalPages-params.Page) * time.Millisecond)
+			return ResponseList[int]{
+				Page:       params.Page,
+				PerPage:    params.Size,
+				TotalItems: totalPages * params.Size,
+				TotalPages: totalPages,
+				Items:      []int{params.Page*10 + 1, params.Page*10 + 2},
+			}, nil
+		}
+
+		resp, err := fullList(context.Background(), ParamsList{Size: 2, Concurrency: 3}, list)
+		require.NoError(t, err)
+		require.Len(t, resp.Items, totalPages*2)
+		for i := 0; i < totalPages; i++ {
+			assert.Equal(t, []int{(i+1)*10 + 1, (i+1)*10 + 2}, resp.Items[i*2:i*2+2])
+		}
+		assert.LessOrEqual(t, maxInFlight.Load(), int32(3))
+		assert.Equal(t, totalPages, resp.TotalPages)
+	})
+
+	t.Run("first error aborts the other requests", func(t *testing.T) {
+		errPage := errors.New("page failed")
+		var canceled atomic.Int32
+		list := func(ctx context.Context, params ParamsList) (ResponseList[int], error) {
+			if params.Page == 3 {
+				return ResponseList[int]{}, errPage
+			}
+			if params.Page > 1 {
+				select {
+				case <-ctx.Done():
+					canceled.Add(1)
+					return ResponseList[int]{}, ctx.Err()
+				case <-time.After(time.Second):
+				}
+			}
+			return ResponseList[int]{Page: params.Page, PerPage: 1, TotalItems: totalPages, TotalPages: totalPages, Items: []int{params.Page}}, nil
+		}
+
+		start := time.Now()
+		_, err := fullList(context.Background(), ParamsList{Size: 1, Concurrency: 4}, list)
+		assert.ErrorIs(t, err, errPage)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Positive(t, canceled.Load())
+	})
+}
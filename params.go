@@ -1,5 +1,13 @@
 package pocketbase
 
+import (
+	"github.com/duke-git/lancet/v2/convertor"
+	"github.com/go-resty/resty/v2"
+)
+
+// defaultPageSize is the page size used by FullList and All when ParamsList.Size is not set.
+const defaultPageSize = 500
+
 type ParamsList struct {
 	Page    int
 	Size    int
@@ -8,5 +16,38 @@ type ParamsList struct {
 	Expand  string
 	Fields  string
 
+	// SkipTotal skips the total counts query, TotalItems and TotalPages are returned as -1.
+	// Recommended for large collections when the total isn't needed.
+	SkipTotal bool
+
+	// Concurrency is the maximum number of pages fetched in parallel by FullList.
+	// The records keep the requested sort order. Values <= 1 fetch the pages sequentially,
+	// as well as SkipTotal, because the number of pages isn't known upfront.
+	Concurrency int
+
 	hackResponseRef any //hack for collection list
 }
+
+func (p ParamsList) setQueryParams(request *resty.Request) {
+	if p.Page > 0 {
+		request.SetQueryParam("page", convertor.ToString(p.Page))
+	}
+	if p.Size > 0 {
+		request.SetQueryParam("perPage", convertor.ToString(p.Size))
+	}
+	if p.Filters != "" {
+		request.SetQueryParam("filter", p.Filters)
+	}
+	if p.Sort != "" {
+		request.SetQueryParam("sort", p.Sort)
+	}
+	if p.Expand != "" {
+		request.SetQueryParam("expand", p.Expand)
+	}
+	if p.Fields != "" {
+		request.SetQueryParam("fields", p.Fields)
+	}
+	if p.SkipTotal {
+		request.SetQueryParam("skipTotal", "1")
+	}
+}
@@ -0,0 +1,191 @@
+package pocketbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/pluja/pocketbase/filter"
+)
+
+// PollingMode defines when a record stream polls the records instead of using the realtime connection,
+// e.g. behind the proxies buffering or dropping SSE.
+type PollingMode int
+
+const (
+	// PollingDisabled uses the realtime connection only.
+	PollingDisabled PollingMode = iota
+	// PollingFallback polls the records when the realtime handshake fails or times out.
+	PollingFallback
+	// PollingAlways polls the records without trying the realtime connection.
+	PollingAlways
+)
+
+const (
+	defaultPollInterval        = 5 * time.Second
+	defaultPollDeletesInterval = time.Minute
+	// defaultHandshakeTimeout is the realtime handshake timeout with PollingFallback.
+	defaultHandshakeTimeout = 10 * time.Second
+)
+
+// poller emulates the realtime events of a stream by listing the changed records.
+type poller[T any] struct {
+	collection *Collection[T]
+	stream     *eventStream[Event[T]]
+	opts       SubscribeOptions
+	targets    []string
+
+	changes        *gapRecovery
+	known          map[string]struct{} // nil if the deletes aren't detected
+	deletesChecked time.Time
+}
+
+// poll opens a stream emulated by listing the records changed since the last poll
+// every opts.PollInterval. The deleted records are detected every opts.PollDeletesInterval
+// by comparing the IDs of all the subscribed records with the previous ones.
+//
+// The failed polls are retried with opts.ReconnectStrategy, but not sooner than the next interval.
+func (c *Collection[T]) poll(ctx context.Context, opts SubscribeOptions, targets []string) (*eventStream[Event[T]], error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	if opts.PollDeletesInterval == 0 {
+		opts.PollDeletesInterval = defaultPollDeletesInterval
+	}
+	if opts.ReconnectStrategy == nil {
+		opts.ReconnectStrategy = &backoff.ZeroBackOff{}
+	}
+
+	stream := newEventStream[Event[T]](opts)
+	ctx, cancel := context.WithCancel(ctx)
+	stream.unsubscribe = cancel
+	go stream.queue.run(stream.channel.C)
+
+	p := &poller[T]{
+		collection: c,
+		stream:     stream,
+		opts:       opts,
+		targets:    targets,
+		changes:    newGapRecovery(),
+	}
+	if opts.PollDeletesInterval > 0 {
+		known, err := c.listIDs(ctx, opts, targets)
+		if err != nil {
+			stream.close(err)
+			return nil, err
+		}
+		p.known = known
+		p.deletesChecked = time.Now()
+	}
+
+	stream.setState(StreamState{Status: StreamConnected, Polling: true})
+	close(stream.ready)
+	go p.run(ctx)
+
+	return stream, nil
+}
+
+func (p *poller[T]) run(ctx context.Context) {
+	attempt := 0
+	timer := time.NewTimer(p.opts.PollInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.stream.Unsubscribe()
+			return
+		case <-timer.C:
+		}
+
+		delay := p.opts.PollInterval
+		if err := p.poll(ctx); err != nil {
+			if ctx.Err() != nil || errors.Is(err, ErrStreamOverflow) {
+				p.stream.Unsubscribe()
+				return
+			}
+			next := p.opts.ReconnectStrategy.NextBackOff()
+			if next == backoff.Stop {
+				p.stream.close(err)
+				return
+			}
+			attempt++
+			p.stream.setState(StreamState{Status: StreamReconnecting, Attempt: attempt, Err: err, Polling: true})
+			delay = max(delay, next)
+		} else if attempt > 0 {
+			attempt = 0
+			p.opts.ReconnectStrategy.Reset()
+			p.stream.setState(StreamState{Status: StreamConnected, Polling: true})
+		}
+		timer.Reset(delay)
+	}
+}
+
+// poll pushes the changed records and, once in opts.PollDeletesInterval, the deleted ones.
+func (p *poller[T]) poll(ctx context.Context) error {
+	ids, err := p.collection.pushChanges(ctx, p.stream, p.changes, p.opts, p.targets)
+	if err != nil || p.known == nil {
+		return err
+	}
+	for _, id := range ids {
+		p.known[id] = struct{}{}
+	}
+	if time.Since(p.deletesChecked) < p.opts.PollDeletesInterval {
+		return nil
+	}
+
+	current, err := p.collection.listIDs(ctx, p.opts, p.targets)
+	if err != nil {
+		return err
+	}
+	var deleted []string
+	for id := range p.known {
+		if _, ok := current[id]; !ok {
+			deleted = append(deleted, id)
+		}
+	}
+	sort.Strings(deleted)
+	for _, id := range deleted {
+		e := Event[T]{Action: "delete"}
+		data, _ := json.Marshal(map[string]string{"id": id}) // map of strings can't fail
+		e.Error = json.Unmarshal(data, &e.Record)
+		if !p.stream.queue.push(e) {
+			p.stream.close(ErrStreamOverflow)
+			return ErrStreamOverflow
+		}
+	}
+	p.known = current
+	p.deletesChecked = time.Now()
+	return nil
+}
+
+// listIDs returns the IDs of all the subscribed records.
+func (c *Collection[T]) listIDs(ctx context.Context, opts SubscribeOptions, targets []string) (map[string]struct{}, error) {
+	filters, err := filter.And(
+		targetsFilter(targets),
+		parenthesize(opts.Filter),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+	params := ParamsList{
+		Fields:    "id",
+		SkipTotal: true,
+		Filters:   filters,
+	}
+	records, err := c.Client.FullListCtx(ctx, c.Name, params)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]struct{}, len(records.Items))
+	for _, record := range records.Items {
+		if id, _ := record["id"].(string); id != "" {
+			ids[id] = struct{}{}
+		}
+	}
+	return ids, nil
+}
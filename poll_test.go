@@ -0,0 +1,91 @@
+package pocketbase
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/pluja/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollection_SubscribePolling(t *testing.T) {
+	tests := []struct {
+		name    string
+		polling PollingMode
+		wantErr bool
+	}{
+		{name: "polling disabled", polling: PollingDisabled, wantErr: true},
+		{name: "polling fallback", polling: PollingFallback},
+		{name: "polling always", polling: PollingAlways},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(defaultURL)
+			client.client.SetRetryCount(0)
+			client.client.OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
+				if r.Method == http.MethodGet && strings.HasSuffix(r.URL, "/api/realtime") {
+					return errors.New("SSE is blocked")
+				}
+				return nil
+			})
+
+			field := "poll_" + time.Now().Format("150405.000")
+			collection := CollectionSet[map[string]any](client, migrations.PostsTimestamps)
+			stream, err := collection.SubscribeWith(SubscribeOptions{
+				Filter:              "field ~ '" + field + "'",
+				Polling:             tt.polling,
+				PollInterval:        100 * time.Millisecond,
+				PollDeletesInterval: 100 * time.Millisecond,
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer stream.Unsubscribe()
+			ch := stream.Events()
+
+			state := stream.State()
+			assert.Equal(t, StreamConnected, state.Status)
+			assert.True(t, state.Polling)
+
+			next := func() Event[map[string]any] {
+				select {
+				case e := <-ch:
+					require.NoError(t, e.Error)
+					return e
+				case <-time.After(5 * time.Second):
+					t.Fatal("no event")
+					return Event[map[string]any]{}
+				}
+			}
+
+			r, err := collection.Create(map[string]any{"field": field})
+			require.NoError(t, err)
+			defer func() { _ = collection.Delete(r.ID) }()
+			e := next()
+			assert.Equal(t, "create", e.Action)
+			assert.Equal(t, r.ID, e.Record["id"])
+
+			require.NoError(t, collection.Update(r.ID, map[string]any{"field": field + "_updated"}))
+			e = next()
+			assert.Equal(t, "update", e.Action)
+			assert.Equal(t, field+"_updated", e.Record["field"])
+
+			require.NoError(t, collection.Delete(r.ID))
+			e = next()
+			assert.Equal(t, "delete", e.Action)
+			assert.Equal(t, r.ID, e.Record["id"])
+
+			stream.Unsubscribe()
+			_, ok := <-ch
+			assert.False(t, ok)
+			assert.NoError(t, stream.Err())
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package pocketbase
+
+import (
+	"errors"
+	"sync"
+)
+
+// defaultStreamBufferSize is the number of events buffered per stream when SubscribeOptions.BufferSize is not set.
+const defaultStreamBufferSize = 100
+
+// ErrStreamOverflow is the stream error when its buffer overflows with the OverflowClose policy.
+var ErrStreamOverflow = errors.New("stream buffer overflow")
+
+// OverflowPolicy defines what happens with a new event when the stream buffer is full,
+// i.e. the consumer doesn't keep up with the events.
+type OverflowPolicy int
+
+const (
+	// OverflowDropOldest drops the oldest buffered event to make space for the new one, it's the default.
+	OverflowDropOldest OverflowPolicy = iota
+	// OverflowDropNewest drops the new event.
+	OverflowDropNewest
+	// OverflowClose closes the stream, its Err returns ErrStreamOverflow.
+	OverflowClose
+	// OverflowBlock waits until there is a free space in the buffer.
+	// No events are lost, but as all the streams of a client share a single connection,
+	// a slow consumer delays the events of the other streams too.
+	OverflowBlock
+)
+
+// eventQueue delivers the events in their order through a bounded buffer.
+//
+// There must be a single producer (the connection reader) calling push
+// and a single consumer calling run.
+type eventQueue[E any] struct {
+	buf    chan E
+	policy OverflowPolicy
+
+	done     chan struct{} // closed to stop the queue
+	stopOnce sync.Once
+	stopped  chan struct{} // closed when run returns
+}
+
+func newEventQueue[E any](size int, policy OverflowPolicy) *eventQueue[E] {
+	if size <= 0 {
+		size = defaultStreamBufferSize
+	}
+	return &eventQueue[E]{
+		buf:     make(chan E, size),
+		policy:  policy,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+// push adds the event to the buffer according to the overflow policy,
+// it returns false if the buffer is full and the policy is OverflowClose.
+func (q *eventQueue[E]) push(e E) bool {
+	select {
+	case q.buf <- e:
+		return true
+	case <-q.done:
+		return true
+	default:
+	}
+
+	switch q.policy {
+	case OverflowDropNewest:
+		return true
+	case OverflowClose:
+		return false
+	case OverflowBlock:
+		select {
+		case q.buf <- e:
+		case <-q.done:
+		}
+		return true
+	default:
+		for {
+			select {
+			case q.buf <- e:
+				return true
+			case <-q.done:
+				return true
+			default:
+			}
+			select {
+			case <-q.buf:
+			default:
+			}
+		}
+	}
+}
+
+// run sends the buffered events to out until the queue is stopped.
+func (q *eventQueue[E]) run(out chan<- E) {
+	defer close(q.stopped)
+	for {
+		select {
+		case e := <-q.buf:
+			select {
+			case out <- e:
+			case <-q.done:
+				return
+			}
+		case <-q.done:
+			return
+		}
+	}
+}
+
+// stop stops the queue and waits until run returns, the buffered events are discarded.
+func (q *eventQueue[E]) stop() {
+	q.stopOnce.Do(func() { close(q.done) })
+	<-q.stopped
+}
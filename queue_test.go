@@ -0,0 +1,77 @@
+package pocketbase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventQueue_Overflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   OverflowPolicy
+		want     []int
+		wantFull bool
+	}{
+		{name: "drop newest", policy: OverflowDropNewest, want: []int{1, 2}},
+		{name: "drop oldest", policy: OverflowDropOldest, want: []int{4, 5}},
+		{name: "default", want: []int{4, 5}},
+		{name: "close", policy: OverflowClose, want: []int{1, 2}, wantFull: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newEventQueue[int](2, tt.policy)
+			full := false
+			for i := 1; i <= 5; i++ {
+				full = !q.push(i) || full
+			}
+			assert.Equal(t, tt.wantFull, full)
+
+			out := make(chan int)
+			go q.run(out)
+			defer q.stop()
+			var got []int
+			for range tt.want {
+				got = append(got, <-out)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEventQueue_Block(t *testing.T) {
+	q := newEventQueue[int](1, OverflowBlock)
+	out := make(chan int)
+
+	pushed := make(chan struct{})
+	go func() {
+		defer close(pushed)
+		for i := 1; i <= 10; i++ {
+			q.push(i)
+		}
+	}()
+
+	select {
+	case <-pushed:
+		t.Fatal("push must block when the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	go q.run(out)
+	for i := 1; i <= 10; i++ {
+		assert.Equal(t, i, <-out)
+	}
+	<-pushed
+
+	// stop unblocks the producer
+	q.push(11)
+	q.push(12)
+	done := make(chan struct{})
+	go func() {
+		q.push(13)
+		close(done)
+	}()
+	q.stop()
+	<-done
+}
@@ -0,0 +1,372 @@
+package pocketbase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/donovanhide/eventsource"
+)
+
+type (
+	// realtime multiplexes all the realtime subscriptions of a client
+	// over a single SSE connection.
+	//
+	// The connection is opened by the first subscription and closed when the last one unsubscribes.
+	// Every change of the subscriptions set is sent to PocketBase in a single request
+	// with the topics of all the subscriptions.
+	realtime struct {
+		client *Client
+
+		mu   sync.Mutex
+		subs map[*subscription]struct{}
+		conn *realtimeConn
+
+		// syncMu serializes the subscriptions set requests, so the last one always wins.
+		syncMu sync.Mutex
+		// notifyMu serializes the state notifications, so they are received in order.
+		notifyMu sync.Mutex
+	}
+
+	// realtimeConn is a single SSE connection, reconnects included.
+	realtimeConn struct {
+		cancel   context.CancelFunc
+		clientID string // empty while (re)connecting
+	}
+
+	// subscription is a single stream registered in the realtime manager.
+	subscription struct {
+		topics []string
+
+		// handle is called from the connection reader for every event with one of the topics.
+		handle func(ev eventsource.Event)
+		// onState is called on every connection state change, it can be nil.
+		onState func(state StreamState)
+
+		ready     chan struct{} // closed when the topics are registered for the first time
+		readyOnce sync.Once
+
+		done     chan struct{} // closed when the subscription is removed
+		doneOnce sync.Once
+		err      error // reason of the removal, nil on unsubscribe
+	}
+)
+
+func newRealtime(client *Client) *realtime {
+	return &realtime{
+		client: client,
+		subs:   make(map[*subscription]struct{}),
+	}
+}
+
+func newSubscription(topics []string, handle func(ev eventsource.Event)) *subscription {
+	return &subscription{
+		topics: topics,
+		handle: handle,
+		ready:  make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+func (s *subscription) matches(topic string) bool {
+	for _, t := range s.topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// markReady reports whether the subscription wasn't ready yet.
+func (s *subscription) markReady() bool {
+	marked := false
+	s.readyOnce.Do(func() {
+		close(s.ready)
+		marked = true
+	})
+	return marked
+}
+
+func (s *subscription) close(err error) {
+	s.doneOnce.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
+
+// subscribe registers the subscription and waits until its topics are subscribed in PocketBase.
+//
+// The first subscription opens the shared connection, which reconnects with the provided strategy
+// until the last subscription is removed.
+func (r *realtime) subscribe(ctx context.Context, sub *subscription, strategy backoff.BackOff) error {
+	r.mu.Lock()
+	r.subs[sub] = struct{}{}
+	conn := r.conn
+	if conn == nil {
+		connCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		conn = &realtimeConn{cancel: cancel}
+		r.conn = conn
+		go r.run(connCtx, conn, strategy)
+	}
+	connected := conn.clientID != ""
+	r.mu.Unlock()
+
+	if connected {
+		if err := r.sync(ctx); err != nil {
+			r.unsubscribe(sub)
+			return err
+		}
+	}
+
+	select {
+	case <-sub.ready:
+		return nil
+	case <-sub.done:
+		return sub.err
+	case <-ctx.Done():
+		r.unsubscribe(sub)
+		return ctx.Err()
+	}
+}
+
+// unsubscribe removes the subscription and updates the subscriptions set in the background,
+// the connection is closed together with the last subscription.
+func (r *realtime) unsubscribe(sub *subscription) {
+	r.remove(sub, nil)
+	go func() {
+		if err := r.sync(context.Background()); err != nil && r.client.sseDebug {
+			log.Printf("SSE unsubscribe: %v", err)
+		}
+	}()
+}
+
+func (r *realtime) remove(sub *subscription, err error) {
+	r.mu.Lock()
+	delete(r.subs, sub)
+	if len(r.subs) == 0 && r.conn != nil {
+		r.conn.cancel()
+		r.conn = nil
+	}
+	r.mu.Unlock()
+	sub.close(err)
+}
+
+// run keeps the connection open until it's canceled.
+// If the very first connection attempt fails, all the waiting subscriptions fail with its error,
+// otherwise every reconnect is retried with the strategy and, once it gives up,
+// all the subscriptions are closed with the last error.
+//
+// All the topics are subscribed again with the new client ID after every reconnect.
+func (r *realtime) run(ctx context.Context, conn *realtimeConn, strategy backoff.BackOff) {
+	d, closeBody, err := r.connect(ctx, conn)
+	if err != nil {
+		r.closeConn(conn, err)
+		return
+	}
+	err = r.read(ctx, conn, d)
+	closeBody()
+	if ctx.Err() != nil {
+		return
+	}
+
+	attempt := 0
+	err = backoff.Retry(func() error {
+		attempt++
+		r.setClientID(conn, "")
+		r.notify(r.snapshot(), StreamState{Status: StreamReconnecting, Attempt: attempt, Err: err})
+
+		d, closeBody, connErr := r.connect(ctx, conn)
+		if connErr != nil {
+			err = connErr
+			return err
+		}
+		defer closeBody()
+		attempt = 0
+		strategy.Reset() // connected, start over on the next disconnect
+		err = r.read(ctx, conn, d)
+		return err
+	}, backoff.WithContext(strategy, ctx))
+	if ctx.Err() != nil {
+		return
+	}
+	if r.client.sseDebug {
+		log.Printf("SSE reconnect failed: %v", err)
+	}
+	r.closeConn(conn, err)
+}
+
+// connect opens the SSE connection, waits for the PB_CONNECT event
+// and subscribes the topics of all the subscriptions.
+func (r *realtime) connect(ctx context.Context, conn *realtimeConn) (*eventsource.Decoder, func(), error) {
+	r.setClientID(conn, "")
+
+	if err := r.client.AuthorizeCtx(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := r.client.client.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true).
+		Get(r.client.url + "/api/realtime")
+	if err != nil {
+		return nil, nil, err
+	}
+	closeBody := func() { resp.RawBody().Close() }
+
+	d := eventsource.NewDecoder(resp.RawBody())
+	ev, err := d.Decode()
+	if err != nil {
+		closeBody()
+		return nil, nil, err
+	}
+	if event := ev.Event(); event != "PB_CONNECT" {
+		closeBody()
+		return nil, nil, fmt.Errorf("first event must be PB_CONNECT, but got %s", event)
+	}
+
+	var s SubscriptionsSet
+	if err := decodeEventData(ev, &s); err != nil {
+		closeBody()
+		return nil, nil, err
+	}
+	r.setClientID(conn, s.ClientID)
+
+	if err := r.sync(ctx); err != nil {
+		closeBody()
+		return nil, nil, err
+	}
+	r.notify(r.snapshot(), StreamState{Status: StreamConnected, ClientID: s.ClientID})
+	return d, closeBody, nil
+}
+
+// read routes the events to the subscriptions until the connection fails.
+func (r *realtime) read(ctx context.Context, conn *realtimeConn, d *eventsource.Decoder) error {
+	for {
+		ev, err := d.Decode()
+		if err != nil {
+			if ctx.Err() != nil {
+				return backoff.Permanent(ctx.Err())
+			}
+			return err
+		}
+		if r.client.sseDebug {
+			log.Printf("SSE event: %+v", ev)
+		}
+
+		r.mu.Lock()
+		if r.conn != conn {
+			r.mu.Unlock()
+			return backoff.Permanent(context.Canceled)
+		}
+		var subs []*subscription
+		for sub := range r.subs {
+			if sub.matches(ev.Event()) {
+				subs = append(subs, sub)
+			}
+		}
+		r.mu.Unlock()
+
+		for _, sub := range subs {
+			sub.handle(ev)
+		}
+	}
+}
+
+// sync sends the topics of all the subscriptions to PocketBase,
+// it's a no-op while the connection isn't established.
+func (r *realtime) sync(ctx context.Context) error {
+	r.syncMu.Lock()
+	defer r.syncMu.Unlock()
+
+	r.mu.Lock()
+	if r.conn == nil || r.conn.clientID == "" {
+		r.mu.Unlock()
+		return nil
+	}
+	clientID := r.conn.clientID
+	subs := make([]*subscription, 0, len(r.subs))
+	unique := make(map[string]struct{})
+	for sub := range r.subs {
+		subs = append(subs, sub)
+		for _, t := range sub.topics {
+			unique[t] = struct{}{}
+		}
+	}
+	r.mu.Unlock()
+
+	topics := make([]string, 0, len(unique))
+	for t := range unique {
+		topics = append(topics, t)
+	}
+	sort.Strings(topics)
+
+	if err := r.client.authSubscribeStream(ctx, clientID, topics); err != nil {
+		return err
+	}
+	var subscribed []*subscription
+	for _, sub := range subs {
+		if sub.markReady() {
+			subscribed = append(subscribed, sub)
+		}
+	}
+	r.notify(subscribed, StreamState{Status: StreamConnected, ClientID: clientID})
+	return nil
+}
+
+// notify sends the state to the subscriptions.
+// The connected state is skipped if the connection was lost in the meantime.
+func (r *realtime) notify(subs []*subscription, state StreamState) {
+	r.notifyMu.Lock()
+	defer r.notifyMu.Unlock()
+
+	if state.Status == StreamConnected {
+		r.mu.Lock()
+		current := r.conn != nil && r.conn.clientID == state.ClientID
+		r.mu.Unlock()
+		if !current {
+			return
+		}
+	}
+	for _, sub := range subs {
+		if sub.onState != nil {
+			sub.onState(state)
+		}
+	}
+}
+
+func (r *realtime) snapshot() []*subscription {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subs := make([]*subscription, 0, len(r.subs))
+	for sub := range r.subs {
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+func (r *realtime) setClientID(conn *realtimeConn, clientID string) {
+	r.mu.Lock()
+	conn.clientID = clientID
+	r.mu.Unlock()
+}
+
+// closeConn closes all the subscriptions of the connection with the error.
+func (r *realtime) closeConn(conn *realtimeConn, err error) {
+	r.mu.Lock()
+	if r.conn != conn {
+		r.mu.Unlock()
+		return
+	}
+	r.conn = nil
+	subs := r.subs
+	r.subs = make(map[*subscription]struct{})
+	r.mu.Unlock()
+
+	conn.cancel()
+	for sub := range subs {
+		sub.close(err)
+	}
+}
@@ -1,9 +1,11 @@
 package pocketbase
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"time"
 )
 
 type (
@@ -27,12 +29,17 @@ type (
 
 // ListAuthMethods returns all available collection auth methods.
 func (c *Collection[T]) ListAuthMethods22() (AuthMethod, error) {
+	return c.ListAuthMethods22Ctx(context.Background())
+}
+
+func (c *Collection[T]) ListAuthMethods22Ctx(ctx context.Context) (AuthMethod, error) {
 	var response AuthMethod
-	if err := c.Authorize(); err != nil {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return response, err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json")
 
 	resp, err := request.Get(c.BaseCollectionPath + "/auth-methods")
@@ -41,11 +48,7 @@ func (c *Collection[T]) ListAuthMethods22() (AuthMethod, error) {
 	}
 
 	if resp.IsError() {
-		return response, fmt.Errorf("[records] pocketbase returned status: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return response, fmt.Errorf("[records] %w", newApiError(resp))
 	}
 
 	if err := json.Unmarshal(resp.Body(), &response); err != nil {
@@ -108,12 +111,17 @@ type AuthMethodsResponse struct {
 
 // ListAuthMethods returns all available collection auth methods.
 func (c *Collection[T]) ListAuthMethods() (AuthMethodsResponse, error) {
+	return c.ListAuthMethodsCtx(context.Background())
+}
+
+func (c *Collection[T]) ListAuthMethodsCtx(ctx context.Context) (AuthMethodsResponse, error) {
 	var response AuthMethodsResponse
-	if err := c.Authorize(); err != nil {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return response, err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json")
 
 	resp, err := request.Get(c.BaseCollectionPath + "/auth-methods")
@@ -122,11 +130,7 @@ func (c *Collection[T]) ListAuthMethods() (AuthMethodsResponse, error) {
 	}
 
 	if resp.IsError() {
-		return response, fmt.Errorf("[records] pocketbase returned status: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return response, fmt.Errorf("[records] %w", newApiError(resp))
 	}
 
 	if err := json.Unmarshal(resp.Body(), &response); err != nil {
@@ -136,11 +140,14 @@ func (c *Collection[T]) ListAuthMethods() (AuthMethodsResponse, error) {
 }
 
 type (
-	AuthWithPasswordResponse struct {
-		Record Record `json:"record"`
+	// AuthWithPasswordResponse is the response of AuthWithPassword, the auth record is decoded as T of the collection.
+	AuthWithPasswordResponse[T any] struct {
+		Record T      `json:"record"`
 		Token  string `json:"token"`
 	}
 
+	// Record has the fields of the default users collection,
+	// use it as T of the collection without a user-defined type (e.g. CollectionSet[pocketbase.Record]).
 	Record struct {
 		Avatar          string `json:"avatar"`
 		CollectionID    string `json:"collectionId"`
@@ -162,18 +169,39 @@ type (
 // the client's AuthStore data and returns:
 // - the authentication token via the AuthWithPasswordResponse
 // - the authenticated record model
-func (c *Collection[T]) AuthWithPassword(username string, password string) (AuthWithPasswordResponse, error) {
-	var response AuthWithPasswordResponse
-	if err := c.Authorize(); err != nil {
+//
+// If the auth record requires multi-factor authentication, the error is MFARequiredError.
+func (c *Collection[T]) AuthWithPassword(username string, password string) (AuthWithPasswordResponse[T], error) {
+	return c.AuthWithPasswordCtx(context.Background(), username, password)
+}
+
+func (c *Collection[T]) AuthWithPasswordCtx(ctx context.Context, username string, password string) (AuthWithPasswordResponse[T], error) {
+	return c.AuthWithPasswordMFACtx(ctx, username, password, "")
+}
+
+// AuthWithPasswordMFA is the same as AuthWithPassword, but it completes the multi-factor authentication
+// started by another method, mfaID is from its MFARequiredError.
+func (c *Collection[T]) AuthWithPasswordMFA(username string, password string, mfaID string) (AuthWithPasswordResponse[T], error) {
+	return c.AuthWithPasswordMFACtx(context.Background(), username, password, mfaID)
+}
+
+func (c *Collection[T]) AuthWithPasswordMFACtx(ctx context.Context, username string, password string, mfaID string) (AuthWithPasswordResponse[T], error) {
+	var response AuthWithPasswordResponse[T]
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return response, err
 	}
 
+	data := map[string]string{
+		"identity": username,
+		"password": password,
+	}
+	if mfaID != "" {
+		data["mfaId"] = mfaID
+	}
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
-		SetMultipartFormData(map[string]string{
-			"identity": username,
-			"password": password,
-		})
+		SetMultipartFormData(data)
 
 	resp, err := request.Post(c.BaseCollectionPath + "/auth-with-password")
 	if err != nil {
@@ -181,41 +209,207 @@ func (c *Collection[T]) AuthWithPassword(username string, password string) (Auth
 	}
 
 	if resp.IsError() {
-		return response, fmt.Errorf("[records] pocketbase returned status at auth-with-password: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return response, fmt.Errorf("[records] auth-with-password: %w", newAuthError(resp))
 	}
 
 	if err := json.Unmarshal(resp.Body(), &response); err != nil {
 		return response, fmt.Errorf("[records] can't unmarshal auth-with-password-response, err %w", err)
 	}
 
-	c.token = response.Token
+	if err := c.authenticated(c.BaseCollectionPath, resp.Body()); err != nil {
+		return response, err
+	}
 	return response, nil
 }
 
-type AuthWithOauth2Response struct {
-	Token string `json:"token"`
+// AuthWithOTPResponse is the response of AuthWithOTP, the auth record is decoded as T of the collection.
+type AuthWithOTPResponse[T any] struct {
+	Record T      `json:"record"`
+	Token  string `json:"token"`
+}
+
+// RequestOTP sends a one-time password to the email of the auth record
+// and returns the OTP id to authenticate with via AuthWithOTP.
+//
+// The OTP id is returned even if there is no auth record with the email.
+func (c *Collection[T]) RequestOTP(email string) (string, error) {
+	return c.RequestOTPCtx(context.Background(), email)
+}
+
+func (c *Collection[T]) RequestOTPCtx(ctx context.Context, email string) (string, error) {
+	if err := c.AuthorizeCtx(ctx); err != nil {
+		return "", err
+	}
+
+	request := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetMultipartFormData(map[string]string{
+			"email": email,
+		})
+
+	resp, err := request.Post(c.BaseCollectionPath + "/request-otp")
+	if err != nil {
+		return "", fmt.Errorf("[records] can't send request-otp request to pocketbase, err %w", err)
+	}
+
+	if resp.IsError() {
+		return "", fmt.Errorf("[records] request-otp: %w", newApiError(resp))
+	}
+
+	var response requestOTPResponse
+	if err := json.Unmarshal(resp.Body(), &response); err != nil {
+		return "", fmt.Errorf("[records] can't unmarshal request-otp-response, err %w", err)
+	}
+	return response.OTPID, nil
+}
+
+// AuthWithOTP authenticate a single auth collection record with the OTP id
+// returned by RequestOTP and the one-time password sent to its email.
+//
+// On success, this method also automatically updates
+// the client's AuthStore data and returns:
+// - the authentication token via the AuthWithOTPResponse
+// - the authenticated record model
+//
+// If the auth record requires multi-factor authentication, the error is MFARequiredError.
+func (c *Collection[T]) AuthWithOTP(otpID string, password string) (AuthWithOTPResponse[T], error) {
+	return c.AuthWithOTPCtx(context.Background(), otpID, password)
+}
+
+func (c *Collection[T]) AuthWithOTPCtx(ctx context.Context, otpID string, password string) (AuthWithOTPResponse[T], error) {
+	return c.AuthWithOTPMFACtx(ctx, otpID, password, "")
+}
+
+// AuthWithOTPMFA is the same as AuthWithOTP, but it completes the multi-factor authentication
+// started by another method, mfaID is from its MFARequiredError.
+func (c *Collection[T]) AuthWithOTPMFA(otpID string, password string, mfaID string) (AuthWithOTPResponse[T], error) {
+	return c.AuthWithOTPMFACtx(context.Background(), otpID, password, mfaID)
+}
+
+func (c *Collection[T]) AuthWithOTPMFACtx(ctx context.Context, otpID string, password string, mfaID string) (AuthWithOTPResponse[T], error) {
+	var response AuthWithOTPResponse[T]
+	if err := c.AuthorizeCtx(ctx); err != nil {
+		return response, err
+	}
+
+	data := map[string]string{
+		"otpId":    otpID,
+		"password": password,
+	}
+	if mfaID != "" {
+		data["mfaId"] = mfaID
+	}
+	request := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetMultipartFormData(data)
+
+	resp, err := request.Post(c.BaseCollectionPath + "/auth-with-otp")
+	if err != nil {
+		return response, fmt.Errorf("[records] can't send auth-with-otp request to pocketbase, err %w", err)
+	}
+
+	if resp.IsError() {
+		return response, fmt.Errorf("[records] auth-with-otp: %w", newAuthError(resp))
+	}
+
+	if err := json.Unmarshal(resp.Body(), &response); err != nil {
+		return response, fmt.Errorf("[records] can't unmarshal auth-with-otp-response, err %w", err)
+	}
+
+	if err := c.authenticated(c.BaseCollectionPath, resp.Body()); err != nil {
+		return response, err
+	}
+	return response, nil
+}
+
+// Impersonate authenticates as the auth record with a non-refreshable token valid for the duration
+// (the auth token duration of the collection if zero) and returns a new client using the token.
+//
+// Only superusers can impersonate, the new client shares the transport settings with this one,
+// which is left untouched.
+func (c *Collection[T]) Impersonate(recordID string, duration time.Duration) (*Client, error) {
+	return c.ImpersonateCtx(context.Background(), recordID, duration)
+}
+
+func (c *Collection[T]) ImpersonateCtx(ctx context.Context, recordID string, duration time.Duration) (*Client, error) {
+	if err := c.AuthorizeCtx(ctx); err != nil {
+		return nil, err
+	}
+
+	request := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"duration": int64(duration / time.Second),
+		})
+
+	resp, err := request.Post(c.BaseCollectionPath + "/impersonate/" + url.PathEscape(recordID))
+	if err != nil {
+		return nil, fmt.Errorf("[records] can't send impersonate request to pocketbase, err %w", err)
+	}
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("[records] impersonate: %w", newApiError(resp))
+	}
+
+	var auth authResponse
+	if err := json.Unmarshal(resp.Body(), &auth); err != nil {
+		return nil, fmt.Errorf("[records] can't unmarshal impersonate-response, err %w", err)
+	}
+
+	client := NewClient(c.url, withTransportOf(c.Client))
+	if err := client.useStaticToken(auth); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// AuthWithOauth2Response is the response of AuthWithOAuth2Code, the auth record is decoded as T of the collection.
+type AuthWithOauth2Response[T any] struct {
+	Record T          `json:"record"`
+	Token  string     `json:"token"`
+	Meta   OAuth2Meta `json:"meta"`
+}
+
+// OAuth2Meta is the OAuth2 account data returned by the provider.
+type OAuth2Meta struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Username     string `json:"username"`
+	Email        string `json:"email"`
+	AvatarURL    string `json:"avatarURL"`
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	Expiry       string `json:"expiry"`
+	// IsNew reports whether the auth record was created by the authentication,
+	// it's sent only by the PocketBase versions newer than v0.23.4.
+	IsNew   bool           `json:"isNew"`
+	RawUser map[string]any `json:"rawUser"`
 }
 
 // AuthWithOAuth2Code authenticate a single auth collection record with OAuth2 code.
 //
-// If you don't have an OAuth2 code you may also want to check `authWithOAuth2` method.
+// If you don't have an OAuth2 code you may also want to check AuthWithOAuth2 method.
 //
 // On success, this method also automatically updates
 // the client's AuthStore data and returns:
 // - the authentication token via the model
 // - the authenticated record model
 // - the OAuth2 account data (eg. name, email, avatar, etc.)
-func (c *Collection[T]) AuthWithOAuth2Code(provider string, code string, codeVerifier string, redirectURL string) (AuthWithOauth2Response, error) {
-	var response AuthWithOauth2Response
-	if err := c.Authorize(); err != nil {
+func (c *Collection[T]) AuthWithOAuth2Code(provider string, code string, codeVerifier string, redirectURL string) (AuthWithOauth2Response[T], error) {
+	return c.AuthWithOAuth2CodeCtx(context.Background(), provider, code, codeVerifier, redirectURL)
+}
+
+func (c *Collection[T]) AuthWithOAuth2CodeCtx(ctx context.Context, provider string, code string, codeVerifier string, redirectURL string) (AuthWithOauth2Response[T], error) {
+	var response AuthWithOauth2Response[T]
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return response, err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetMultipartFormData(map[string]string{
 			"provider":     provider,
@@ -231,49 +425,40 @@ func (c *Collection[T]) AuthWithOAuth2Code(provider string, code string, codeVer
 	}
 
 	if resp.IsError() {
-		return response, fmt.Errorf("[records] pocketbase returned status at auth-with-oauth2: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return response, fmt.Errorf("[records] auth-with-oauth2: %w", newAuthError(resp))
 	}
 
 	if err := json.Unmarshal(resp.Body(), &response); err != nil {
 		return response, fmt.Errorf("[records] can't unmarshal auth-with-oauth2-response, err %w", err)
 	}
 
-	c.token = response.Token
+	if err := c.authenticated(c.BaseCollectionPath, resp.Body()); err != nil {
+		return response, err
+	}
 	return response, nil
 }
 
-type AuthRefreshResponse struct {
-	Record struct {
-		Avatar          string `json:"avatar"`
-		CollectionID    string `json:"collectionId"`
-		CollectionName  string `json:"collectionName"`
-		Created         string `json:"created"`
-		Email           string `json:"email"`
-		EmailVisibility bool   `json:"emailVisibility"`
-		ID              string `json:"id"`
-		Name            string `json:"name"`
-		Updated         string `json:"updated"`
-		Username        string `json:"username"`
-		Verified        bool   `json:"verified"`
-	} `json:"record"`
-	Token string `json:"token"`
+// AuthRefreshResponse is the response of AuthRefresh, the auth record is decoded as T of the collection.
+type AuthRefreshResponse[T any] struct {
+	Record T      `json:"record"`
+	Token  string `json:"token"`
 }
 
 // AuthRefresh refreshes the current authenticated record instance and
 // * returns a new token and record data.
-func (c *Collection[T]) AuthRefresh() (AuthRefreshResponse, error) {
-	var response AuthRefreshResponse
-	if err := c.Authorize(); err != nil {
+func (c *Collection[T]) AuthRefresh() (AuthRefreshResponse[T], error) {
+	return c.AuthRefreshCtx(context.Background())
+}
+
+func (c *Collection[T]) AuthRefreshCtx(ctx context.Context) (AuthRefreshResponse[T], error) {
+	var response AuthRefreshResponse[T]
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return response, err
 	}
 
 	request := c.client.R().
-		SetHeader("Content-Type", "application/json").
-		SetAuthToken(c.token)
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json")
 
 	resp, err := request.Post(c.BaseCollectionPath + "/auth-refresh")
 	if err != nil {
@@ -281,28 +466,31 @@ func (c *Collection[T]) AuthRefresh() (AuthRefreshResponse, error) {
 	}
 
 	if resp.IsError() {
-		return response, fmt.Errorf("[records] pocketbase returned status at auth-refresh: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return response, fmt.Errorf("[records] auth-refresh: %w", newApiError(resp))
 	}
 
 	if err := json.Unmarshal(resp.Body(), &response); err != nil {
 		return response, fmt.Errorf("[records] can't unmarshal auth-refresh-response, err %w", err)
 	}
 
-	c.token = response.Token
+	if err := c.authenticated(c.BaseCollectionPath, resp.Body()); err != nil {
+		return response, err
+	}
 	return response, nil
 }
 
 // RequestVerification sends auth record verification email request.
 func (c *Collection[T]) RequestVerification(email string) error {
-	if err := c.Authorize(); err != nil {
+	return c.RequestVerificationCtx(context.Background(), email)
+}
+
+func (c *Collection[T]) RequestVerificationCtx(ctx context.Context, email string) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetMultipartFormData(map[string]string{
 			"email": email,
@@ -313,11 +501,7 @@ func (c *Collection[T]) RequestVerification(email string) error {
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("[records] pocketbase returned status at request-verification: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[records] request-verification: %w", newApiError(resp))
 	}
 	return nil
 }
@@ -327,11 +511,16 @@ func (c *Collection[T]) RequestVerification(email string) error {
 // If the current `client.authStore.model` matches with the auth record from the token,
 // then on success the `client.authStore.model.verified` will be updated to `true`.
 func (c *Collection[T]) ConfirmVerification(verificationToken string) error {
-	if err := c.Authorize(); err != nil {
+	return c.ConfirmVerificationCtx(context.Background(), verificationToken)
+}
+
+func (c *Collection[T]) ConfirmVerificationCtx(ctx context.Context, verificationToken string) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetMultipartFormData(map[string]string{
 			"token": verificationToken,
@@ -342,22 +531,23 @@ func (c *Collection[T]) ConfirmVerification(verificationToken string) error {
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("[records] pocketbase returned status at confirm-verification: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[records] confirm-verification: %w", newApiError(resp))
 	}
 	return nil
 }
 
 // RequestPasswordReset sends auth record password reset request
 func (c *Collection[T]) RequestPasswordReset(email string) error {
-	if err := c.Authorize(); err != nil {
+	return c.RequestPasswordResetCtx(context.Background(), email)
+}
+
+func (c *Collection[T]) RequestPasswordResetCtx(ctx context.Context, email string) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetMultipartFormData(map[string]string{
 			"email": email,
@@ -368,22 +558,23 @@ func (c *Collection[T]) RequestPasswordReset(email string) error {
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("[records] pocketbase returned status at request-password-reset: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[records] request-password-reset: %w", newApiError(resp))
 	}
 	return nil
 }
 
 // ConfirmPasswordReset confirms auth record password reset request.
 func (c *Collection[T]) ConfirmPasswordReset(passwordResetToken string, password string, passwordConfirm string) error {
-	if err := c.Authorize(); err != nil {
+	return c.ConfirmPasswordResetCtx(context.Background(), passwordResetToken, password, passwordConfirm)
+}
+
+func (c *Collection[T]) ConfirmPasswordResetCtx(ctx context.Context, passwordResetToken string, password string, passwordConfirm string) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetMultipartFormData(map[string]string{
 			"token":           passwordResetToken,
@@ -396,27 +587,27 @@ func (c *Collection[T]) ConfirmPasswordReset(passwordResetToken string, password
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("[records] pocketbase returned status at confirm-password-reset: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[records] confirm-password-reset: %w", newApiError(resp))
 	}
 	return nil
 }
 
 // RequestEmailChange sends an email change request to the authenticated record model.
 func (c *Collection[T]) RequestEmailChange(newEmail string) error {
-	if err := c.Authorize(); err != nil {
+	return c.RequestEmailChangeCtx(context.Background(), newEmail)
+}
+
+func (c *Collection[T]) RequestEmailChangeCtx(ctx context.Context, newEmail string) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetMultipartFormData(map[string]string{
 			"newEmail": newEmail,
-		}).
-		SetAuthToken(c.token)
+		})
 
 	resp, err := request.Post(c.BaseCollectionPath + "/request-email-change")
 	if err != nil {
@@ -424,28 +615,28 @@ func (c *Collection[T]) RequestEmailChange(newEmail string) error {
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("[records] pocketbase returned status at request-email-change: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[records] request-email-change: %w", newApiError(resp))
 	}
 	return nil
 }
 
 // ConfirmEmailChange confirms auth record's new email address.
 func (c *Collection[T]) ConfirmEmailChange(emailChangeToken string, password string) error {
-	if err := c.Authorize(); err != nil {
+	return c.ConfirmEmailChangeCtx(context.Background(), emailChangeToken, password)
+}
+
+func (c *Collection[T]) ConfirmEmailChangeCtx(ctx context.Context, emailChangeToken string, password string) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetMultipartFormData(map[string]string{
 			"token":    emailChangeToken,
 			"password": password,
-		}).
-		SetAuthToken(c.token)
+		})
 
 	resp, err := request.Post(c.BaseCollectionPath + "/confirm-email-change")
 	if err != nil {
@@ -453,11 +644,7 @@ func (c *Collection[T]) ConfirmEmailChange(emailChangeToken string, password str
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("[records] pocketbase returned status at confirm-email-change: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[records] confirm-email-change: %w", newApiError(resp))
 	}
 	return nil
 }
@@ -473,13 +660,19 @@ type ExternalAuthRequest struct {
 }
 
 // ListExternalAuths lists all linked external auth providers for the specified auth record.
+// It's for PocketBase v0.22, use Client.ExternalAuths with v0.23+.
 func (c *Collection[T]) ListExternalAuths22(recordID string) ([]ExternalAuthRequest, error) {
+	return c.ListExternalAuths22Ctx(context.Background(), recordID)
+}
+
+func (c *Collection[T]) ListExternalAuths22Ctx(ctx context.Context, recordID string) ([]ExternalAuthRequest, error) {
 	var response []ExternalAuthRequest
-	if err := c.Authorize(); err != nil {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return response, err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json")
 
 	resp, err := request.Get(c.baseCrudPath() + url.QueryEscape(recordID) + "/external-auths")
@@ -488,11 +681,7 @@ func (c *Collection[T]) ListExternalAuths22(recordID string) ([]ExternalAuthRequ
 	}
 
 	if resp.IsError() {
-		return response, fmt.Errorf("[records] pocketbase request for list external-auths returned status: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return response, fmt.Errorf("[records] list external-auths: %w", newApiError(resp))
 	}
 
 	if err := json.Unmarshal(resp.Body(), &response); err != nil {
@@ -502,12 +691,18 @@ func (c *Collection[T]) ListExternalAuths22(recordID string) ([]ExternalAuthRequ
 }
 
 // UnlinkExternalAuth unlink a single external auth provider from the specified auth record.
+// It's for PocketBase v0.22, use Client.ExternalAuths with v0.23+.
 func (c *Collection[T]) UnlinkExternalAuth22(recordID string, provider string) error {
-	if err := c.Authorize(); err != nil {
+	return c.UnlinkExternalAuth22Ctx(context.Background(), recordID, provider)
+}
+
+func (c *Collection[T]) UnlinkExternalAuth22Ctx(ctx context.Context, recordID string, provider string) error {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return err
 	}
 
 	request := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json")
 
 	resp, err := request.Delete(c.baseCrudPath() + url.QueryEscape(recordID) + "/external-auths/" + url.QueryEscape(provider))
@@ -516,11 +711,7 @@ func (c *Collection[T]) UnlinkExternalAuth22(recordID string, provider string) e
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("[records] pocketbase returned status at unlink-external-auth-: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return fmt.Errorf("[records] unlink-external-auth: %w", newApiError(resp))
 	}
 	return nil
 }
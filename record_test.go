@@ -1,6 +1,9 @@
 package pocketbase
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -65,8 +68,196 @@ func TestCollection_AuthWithPassword(t *testing.T) {
 	})
 }
 
-func TestCollection_AuthWithOauth2(_ *testing.T) {
-	// actually I don't know how to test
+// testAuthRecord creates a verified auth record with a unique email, used as its password too.
+// The OTP and MFA tests need their own records, the OTP attempts are rate limited per auth record.
+func testAuthRecord(t *testing.T, collection string) string {
+	admin := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	email := fmt.Sprintf("%d@%s.com", time.Now().UnixNano(), collection)
+	r, err := admin.Create(collection, map[string]any{
+		"email":           email,
+		"password":        email,
+		"passwordConfirm": email,
+		"verified":        true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = admin.Delete(collection, r.ID)
+	})
+	return email
+}
+
+// testOTPPassword returns the one-time password kept by the test server instead of sending it.
+func testOTPPassword(ctx context.Context, otpID string) (string, error) {
+	admin := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	if err := admin.AuthorizeCtx(ctx); err != nil {
+		return "", err
+	}
+	// the email is sent in the background
+	for i := 0; i < 50; i++ {
+		var result struct {
+			Password string `json:"password"`
+		}
+		resp, err := admin.client.R().SetContext(ctx).SetResult(&result).Get(defaultURL + "/api/test/otp/" + otpID)
+		if err != nil {
+			return "", err
+		}
+		if resp.IsSuccess() {
+			return result.Password, nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return "", errors.New("no one-time password sent")
+}
+
+func TestCollection_AuthWithPassword_Typed(t *testing.T) {
+	type member struct {
+		ID             string `json:"id"`
+		Email          string `json:"email"`
+		CollectionName string `json:"collectionName"`
+	}
+	client := NewClient(defaultURL)
+
+	response, err := CollectionSet[member](client, "users").AuthWithPassword(migrations.UserEmailPassword, migrations.UserEmailPassword)
+	require.NoError(t, err)
+	assert.Equal(t, migrations.UserEmailPassword, response.Record.Email)
+	assert.Equal(t, "users", response.Record.CollectionName)
+
+	refreshed, err := CollectionSet[map[string]any](client, "users").AuthRefresh()
+	require.NoError(t, err)
+	assert.Equal(t, response.Record.ID, refreshed.Record["id"])
+	assert.Contains(t, refreshed.Record, "avatar")
+}
+
+func TestCollection_AuthWithOTP(t *testing.T) {
+	t.Run("authenticate with valid one-time password", func(t *testing.T) {
+		defaultClient := NewClient(defaultURL)
+		collection := CollectionSet[Record](defaultClient, migrations.UsersOTP)
+		email := testAuthRecord(t, migrations.UsersOTP)
+
+		otpID, err := collection.RequestOTP(email)
+		require.NoError(t, err)
+		require.NotEmpty(t, otpID)
+		password, err := testOTPPassword(context.Background(), otpID)
+		require.NoError(t, err)
+
+		response, err := collection.AuthWithOTP(otpID, password)
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.Token)
+		assert.Equal(t, email, response.Record.Email)
+		assert.Equal(t, response.Token, defaultClient.token)
+		assert.Contains(t, string(defaultClient.AuthRecord()), email)
+
+		// the one-time password can be used only once
+		_, err = collection.AuthWithOTP(otpID, password)
+		assert.Error(t, err)
+	})
+
+	t.Run("authenticate with invalid one-time password", func(t *testing.T) {
+		defaultClient := NewClient(defaultURL)
+		collection := CollectionSet[User](defaultClient, migrations.UsersOTP)
+
+		otpID, err := collection.RequestOTP(testAuthRecord(t, migrations.UsersOTP))
+		require.NoError(t, err)
+		response, err := collection.AuthWithOTP(otpID, "invalid")
+		assert.Error(t, err)
+		assert.Empty(t, response.Token)
+		assert.Empty(t, defaultClient.token)
+	})
+
+	t.Run("request one-time password with disabled OTP", func(t *testing.T) {
+		_, err := CollectionSet[User](NewClient(defaultURL), "users").RequestOTP(migrations.UserEmailPassword)
+		assert.Error(t, err)
+	})
+}
+
+func TestCollection_AuthWithMFA(t *testing.T) {
+	t.Run("password and one-time password", func(t *testing.T) {
+		email := testAuthRecord(t, migrations.UsersMFA)
+		defaultClient := NewClient(defaultURL)
+		collection := CollectionSet[User](defaultClient, migrations.UsersMFA)
+
+		_, err := collection.AuthWithPassword(email, email)
+		require.ErrorIs(t, err, ErrMFARequired)
+		assert.True(t, IsUnauthorized(err))
+		var mfaErr *MFARequiredError
+		require.ErrorAs(t, err, &mfaErr)
+		assert.NotEmpty(t, mfaErr.MFAID)
+		assert.Empty(t, defaultClient.token)
+
+		otpID, err := collection.RequestOTP(email)
+		require.NoError(t, err)
+		password, err := testOTPPassword(context.Background(), otpID)
+		require.NoError(t, err)
+
+		response, err := collection.AuthWithOTPMFA(otpID, password, mfaErr.MFAID)
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.Token)
+		assert.Equal(t, response.Token, defaultClient.token)
+	})
+
+	t.Run("one-time password and password", func(t *testing.T) {
+		email := testAuthRecord(t, migrations.UsersMFA)
+		defaultClient := NewClient(defaultURL)
+		collection := CollectionSet[User](defaultClient, migrations.UsersMFA)
+
+		otpID, err := collection.RequestOTP(email)
+		require.NoError(t, err)
+		password, err := testOTPPassword(context.Background(), otpID)
+		require.NoError(t, err)
+		_, err = collection.AuthWithOTP(otpID, password)
+		var mfaErr *MFARequiredError
+		require.ErrorAs(t, err, &mfaErr)
+
+		// the second factor must be another method
+		_, err = collection.AuthWithOTPMFA(otpID, password, mfaErr.MFAID)
+		assert.Error(t, err)
+
+		response, err := collection.AuthWithPasswordMFA(email, email, mfaErr.MFAID)
+		require.NoError(t, err)
+		assert.Equal(t, response.Token, defaultClient.token)
+	})
+
+	t.Run("the other errors", func(t *testing.T) {
+		email := testAuthRecord(t, migrations.UsersMFA)
+		_, err := CollectionSet[User](NewClient(defaultURL), migrations.UsersMFA).AuthWithPassword(email, "invalid")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrMFARequired)
+	})
+}
+
+func TestCollection_Impersonate(t *testing.T) {
+	admin := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	users, err := admin.List("users", ParamsList{Filters: "email='" + migrations.UserEmailPassword + "'"})
+	require.NoError(t, err)
+	require.Len(t, users.Items, 1)
+	userID := users.Items[0]["id"].(string)
+
+	t.Run("impersonate as superuser", func(t *testing.T) {
+		require.NoError(t, admin.Authorize())
+		adminToken := admin.AuthStore().Token()
+
+		client, err := CollectionSet[User](admin, "users").Impersonate(userID, time.Minute)
+		require.NoError(t, err)
+		assert.Contains(t, string(client.AuthRecord()), userID)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), tokenExpiry(client.AuthStore().Token()), 5*time.Second)
+
+		r, err := client.List(migrations.PostsUser, ParamsList{})
+		require.NoError(t, err)
+		assert.NotZero(t, r.TotalItems)
+		_, err = client.Collections().List(ParamsList{})
+		assert.Error(t, err)
+
+		// the superuser client is untouched
+		assert.Equal(t, adminToken, admin.AuthStore().Token())
+		_, err = admin.Collections().List(ParamsList{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("impersonate as user", func(t *testing.T) {
+		user := NewClient(defaultURL, WithUserEmailPassword(migrations.UserEmailPassword, migrations.UserEmailPassword))
+		_, err := CollectionSet[User](user, "users").Impersonate(userID, 0)
+		assert.True(t, IsForbidden(err))
+	})
 }
 
 func TestCollection_AuthRefresh(t *testing.T) {
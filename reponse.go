@@ -14,3 +14,12 @@ type ResponseCreate struct {
 	Field   string `json:"field"`
 	Updated string `json:"updated"`
 }
+
+// ResponseCursor is a single page of records returned by the keyset (cursor) pagination.
+type ResponseCursor[T any] struct {
+	Items []T `json:"items"`
+
+	// NextCursor continues the listing right after the last returned record.
+	// It's empty when the page isn't full, i.e. there are no more records.
+	NextCursor string `json:"nextCursor"`
+}
@@ -0,0 +1,231 @@
+package pocketbase
+
+import (
+	"context"
+	"sync"
+
+	"github.com/SierraSoftworks/multicast/v2"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/donovanhide/eventsource"
+)
+
+// openStream subscribes the topics in the shared realtime connection of the client,
+// every event of the topics is converted with decode. The optional onReconnected
+// is called after every reconnect, before the live events are resumed.
+//
+// All the streams of a client share a single realtime connection,
+// which is reconnected with opts.ReconnectStrategy of the stream that opened it.
+func openStream[E any](
+	ctx context.Context,
+	c *Client,
+	opts SubscribeOptions,
+	targets []string,
+	decode func(ev eventsource.Event) E,
+	onReconnected func(ctx context.Context, s *eventStream[E]),
+) (*eventStream[E], error) {
+	topics := make([]string, 0, len(targets))
+	for _, t := range targets {
+		topics = append(topics, opts.topic(t))
+	}
+	if opts.ReconnectStrategy == nil {
+		opts.ReconnectStrategy = &backoff.ZeroBackOff{}
+	}
+
+	stream := newEventStream[E](opts)
+	sub := newSubscription(topics, func(ev eventsource.Event) {
+		if !stream.queue.push(decode(ev)) {
+			stream.close(ErrStreamOverflow)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(ctx)
+	sub.onState = func(state StreamState) {
+		reconnected := state.Status == StreamConnected && stream.State().Status == StreamReconnecting
+		stream.setState(state)
+		if reconnected && onReconnected != nil {
+			onReconnected(ctx, stream)
+		}
+	}
+	stream.unsubscribe = func() {
+		cancel()
+		c.realtime.unsubscribe(sub)
+	}
+	go stream.queue.run(stream.channel.C)
+
+	handshakeCtx := ctx
+	if opts.HandshakeTimeout > 0 {
+		var cancelHandshake context.CancelFunc
+		handshakeCtx, cancelHandshake = context.WithTimeout(ctx, opts.HandshakeTimeout)
+		defer cancelHandshake()
+	}
+	if err := c.realtime.subscribe(handshakeCtx, sub, opts.ReconnectStrategy); err != nil {
+		stream.close(err)
+		return nil, err
+	}
+	close(stream.ready)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			stream.Unsubscribe()
+		case <-sub.done:
+			stream.close(sub.err)
+		}
+	}()
+
+	return stream, nil
+}
+
+// eventStream delivers the events of the subscribed topics,
+// it's shared by the record and the custom topic streams.
+type eventStream[E any] struct {
+	channel     *multicast.Channel[E]
+	queue       *eventQueue[E]
+	unsubscribe func()
+
+	ready       chan struct{}
+	onceCleanup *sync.Once
+
+	mu     sync.Mutex
+	err    error
+	state  StreamState
+	states chan StreamState
+}
+
+// StreamStatus is the realtime connection status of a stream.
+type StreamStatus int
+
+const (
+	// StreamConnecting is the initial status until the topics are subscribed.
+	StreamConnecting StreamStatus = iota
+	// StreamConnected means the topics are subscribed and the events are received.
+	StreamConnected
+	// StreamReconnecting means the connection was lost, the events sent
+	// until the next StreamConnected state are missed.
+	StreamReconnecting
+	// StreamClosed is the final status after Unsubscribe or a failure, see Err of the stream.
+	StreamClosed
+)
+
+// StreamState is a change of the stream connection status.
+type StreamState struct {
+	Status StreamStatus
+	// ClientID is the realtime client ID of the StreamConnected connection.
+	ClientID string
+	// Attempt is the StreamReconnecting attempt number, starting from 1.
+	Attempt int
+	// Err is the error that caused StreamReconnecting or StreamClosed.
+	Err error
+	// Polling reports the records are polled instead of using the realtime connection,
+	// see SubscribeOptions.Polling.
+	Polling bool
+}
+
+// streamStatesSize is the number of buffered state changes, the oldest are dropped when full.
+const streamStatesSize = 16
+
+func newEventStream[E any](opts SubscribeOptions) *eventStream[E] {
+	s := &eventStream[E]{
+		channel:     multicast.New[E](),
+		queue:       newEventQueue[E](opts.BufferSize, opts.OverflowPolicy),
+		ready:       make(chan struct{}),
+		onceCleanup: &sync.Once{},
+		states:      make(chan StreamState, streamStatesSize),
+	}
+	s.states <- s.state
+	return s
+}
+
+// Events returns a channel with the stream events in the order they were sent by PocketBase.
+// It's closed when the stream is unsubscribed or fails, see Err.
+func (s *eventStream[E]) Events() <-chan E {
+	return s.channel.Listen().C
+}
+
+// States returns a channel with the connection state changes, starting with StreamConnecting.
+// It's closed after the StreamClosed state. If the changes aren't received,
+// the oldest are dropped, the current state is always available with State.
+//
+// After every reconnect all the topics are subscribed again before StreamConnected is sent,
+// but the events sent while StreamReconnecting are missed.
+func (s *eventStream[E]) States() <-chan StreamState {
+	return s.states
+}
+
+// State returns the current connection state.
+func (s *eventStream[E]) State() StreamState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+func (s *eventStream[E]) Unsubscribe() {
+	s.close(nil)
+}
+
+// Err returns the reason the stream was closed, e.g. ErrStreamOverflow
+// or the last reconnect error. It's nil while the stream is open or after Unsubscribe.
+func (s *eventStream[E]) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s StreamStatus) String() string {
+	switch s {
+	case StreamConnecting:
+		return "connecting"
+	case StreamConnected:
+		return "connected"
+	case StreamReconnecting:
+		return "reconnecting"
+	case StreamClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+func (s *eventStream[E]) setState(state StreamState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state.Status == StreamClosed ||
+		state.Status == StreamConnected && s.state.Status == StreamConnected && s.state.ClientID == state.ClientID {
+		return
+	}
+	s.state = state
+	for {
+		select {
+		case s.states <- state:
+			if state.Status == StreamClosed {
+				close(s.states)
+			}
+			return
+		default:
+			<-s.states // drop the oldest
+		}
+	}
+}
+
+func (s *eventStream[E]) close(err error) {
+	s.onceCleanup.Do(func() {
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+
+		s.unsubscribe()
+		s.queue.stop()
+		s.channel.Close()
+		s.setState(StreamState{Status: StreamClosed, Err: err})
+	})
+}
+
+// Deprecated: use <-stream.Ready() instead of
+func (s *eventStream[E]) WaitAuthReady() error {
+	<-s.ready
+	return nil
+}
+
+func (s *eventStream[E]) Ready() <-chan struct{} {
+	return s.ready
+}
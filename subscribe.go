@@ -6,13 +6,19 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"sync"
+	"net/url"
+	"strings"
+	"time"
 
-	"github.com/SierraSoftworks/multicast/v2"
 	"github.com/cenkalti/backoff/v4"
 	"github.com/donovanhide/eventsource"
 )
 
+// Stream is a realtime stream of the record events.
+type Stream[T any] struct {
+	*eventStream[Event[T]]
+}
+
 type Event[T any] struct {
 	Action string `json:"action"`
 	Record T      `json:"record"`
@@ -20,18 +26,100 @@ type Event[T any] struct {
 }
 
 func (c *Collection[T]) Subscribe(targets ...string) (*Stream[T], error) {
+	return c.SubscribeCtx(context.Background(), targets...)
+}
+
+// SubscribeCtx is the same as Subscribe, but the stream is closed
+// as soon as the provided context is done.
+func (c *Collection[T]) SubscribeCtx(ctx context.Context, targets ...string) (*Stream[T], error) {
 	opts := SubscribeOptions{
 		ReconnectStrategy: &backoff.ZeroBackOff{},
 	}
-	return c.SubscribeWith(opts, targets...)
+	return c.SubscribeWithCtx(ctx, opts, targets...)
 }
 
 type SubscribeOptions struct {
 	ReconnectStrategy backoff.BackOff
+
+	// BufferSize is the number of events buffered for a slow consumer, 100 by default.
+	BufferSize int
+	// OverflowPolicy defines what happens when the buffer is full, OverflowDropOldest by default,
+	// so a slow consumer doesn't delay the other streams sharing the connection.
+	OverflowPolicy OverflowPolicy
+
+	// Filter, Expand and Fields are applied to the record of every event,
+	// the same as ParamsList.Filters, ParamsList.Expand and ParamsList.Fields.
+	Filter string
+	Expand string
+	Fields string
+	// Query holds additional query params, available in the API rules as @request.query.*.
+	Query map[string]string
+	// Headers are available in the API rules as @request.headers.*,
+	// PocketBase normalizes the names to snake case (e.g. "X-Token" is "x_token").
+	Headers map[string]string
+
+	// RecoverGaps lists the records changed while reconnecting and sends them as "create"
+	// or "update" events before the live ones. The changes are matched by the "updated" field
+	// of the collection records (the client clock is used until the first event is received),
+	// the records deleted while reconnecting aren't detected.
+	RecoverGaps bool
+
+	// HandshakeTimeout limits the wait for the realtime connection and the topics subscription,
+	// 10 seconds with PollingFallback and unlimited otherwise.
+	HandshakeTimeout time.Duration
+	// Polling selects when the records are polled instead of using the realtime connection,
+	// the polled streams emit the same events, but the records that stop matching the Filter
+	// are sent as "delete" events. Query and Headers aren't applied to the polling requests.
+	Polling PollingMode
+	// PollInterval is the interval between the polls of the changed records, 5 seconds by default.
+	PollInterval time.Duration
+	// PollDeletesInterval is the interval of the deleted records detection (the IDs of all the records
+	// are listed), 1 minute by default. The deletes aren't detected if it's negative.
+	PollDeletesInterval time.Duration
+}
+
+// topic appends the subscription options to the topic, e.g.
+//
+//	posts/*?options={"query":{"filter":"status='published'"},"headers":{"x_token":"abc"}}
+func (o SubscribeOptions) topic(topic string) string {
+	query := make(map[string]string, len(o.Query)+3)
+	for k, v := range o.Query {
+		query[k] = v
+	}
+	if o.Filter != "" {
+		query["filter"] = o.Filter
+	}
+	if o.Expand != "" {
+		query["expand"] = o.Expand
+	}
+	if o.Fields != "" {
+		query["fields"] = o.Fields
+	}
+	if len(query) == 0 && len(o.Headers) == 0 || strings.Contains(topic, "?options=") {
+		return topic
+	}
+
+	options := map[string]map[string]string{}
+	if len(query) > 0 {
+		options["query"] = query
+	}
+	if len(o.Headers) > 0 {
+		options["headers"] = o.Headers
+	}
+	data, _ := json.Marshal(options) // maps of strings can't fail
+	return topic + "?options=" + url.QueryEscape(string(data))
 }
 
 func (c *Collection[T]) SubscribeWith(opts SubscribeOptions, targets ...string) (*Stream[T], error) {
-	if err := c.Authorize(); err != nil {
+	return c.SubscribeWithCtx(context.Background(), opts, targets...)
+}
+
+// SubscribeWithCtx subscribes to the targets (the collection name by default).
+//
+// All the streams of the client share a single realtime connection,
+// which is reconnected with opts.ReconnectStrategy of the stream that opened it.
+func (c *Collection[T]) SubscribeWithCtx(ctx context.Context, opts SubscribeOptions, targets ...string) (*Stream[T], error) {
+	if err := c.AuthorizeCtx(ctx); err != nil {
 		return nil, err
 	}
 
@@ -39,72 +127,46 @@ func (c *Collection[T]) SubscribeWith(opts SubscribeOptions, targets ...string)
 		targets = []string{c.Name}
 	}
 
-	stream := newStream[T]()
-	ctx, cancel := context.WithCancel(context.Background())
-	stream.unsubscribe = func() { cancel() }
+	var gap *gapRecovery
+	var onReconnected func(ctx context.Context, s *eventStream[Event[T]])
+	if opts.RecoverGaps {
+		gap = newGapRecovery()
+		onReconnected = func(ctx context.Context, s *eventStream[Event[T]]) {
+			c.recoverGap(ctx, s, gap, opts, targets)
+		}
+	}
 
-	handleSSEEvent := func(ev eventsource.Event) {
-		var e Event[T]
-		if c.sseDebug {
-			log.Printf("SSE event: %+v", ev)
+	switch opts.Polling {
+	case PollingAlways:
+		stream, err := c.poll(ctx, opts, targets)
+		if err != nil {
+			return nil, err
 		}
-		e.Error = json.Unmarshal([]byte(ev.Data()), &e)
-		stream.channel.C <- e
-	}
-
-	once := &sync.Once{}
-	stream.ready.Lock()
-	startStream := func(check bool) func() error {
-		return func() (err error) {
-			req := c.client.R().SetContext(ctx).SetDoNotParseResponse(true)
-			resp, err := req.Get(c.url + "/api/realtime")
-			defer resp.RawBody().Close()
-			if err != nil {
-				return
-			}
-
-			d := eventsource.NewDecoder(resp.RawBody())
-
-			ev, err := d.Decode()
-			if err != nil {
-				return err
-			}
-			if event := ev.Event(); event != "PB_CONNECT" {
-				return fmt.Errorf("first event must be PB_CONNECT, but got %s", event)
-			}
-
-			if err := c.authSubscribeStream([]byte(ev.Data()), targets); err != nil {
-				return err
-			}
-
-			if !check {
-				once.Do(func() {
-					stream.ready.Unlock()
-				})
-				for {
-					ev, err := d.Decode()
-					if err != nil {
-						return err
-					}
-					go handleSSEEvent(ev)
-				}
-			}
-
-			return nil
+		return &Stream[T]{stream}, nil
+	case PollingFallback:
+		if opts.HandshakeTimeout == 0 {
+			opts.HandshakeTimeout = defaultHandshakeTimeout
 		}
 	}
 
-	if err := startStream(true)(); err != nil {
+	stream, err := openStream(ctx, c.Client, opts, targets, func(ev eventsource.Event) Event[T] {
+		if gap != nil {
+			gap.seenEvent(ev)
+		}
+		var e Event[T]
+		e.Error = decodeEventData(ev, &e)
+		return e
+	}, onReconnected)
+	if err != nil && opts.Polling == PollingFallback && ctx.Err() == nil {
+		if c.sseDebug {
+			log.Printf("SSE handshake failed, falling back to polling: %v", err)
+		}
+		stream, err = c.poll(ctx, opts, targets)
+	}
+	if err != nil {
 		return nil, err
 	}
-
-	go func() {
-		if err := backoff.Retry(startStream(false), backoff.WithContext(opts.ReconnectStrategy, ctx)); err != nil {
-			log.Print(err)
-		}
-	}()
-
-	return stream, nil
+	return &Stream[T]{stream}, nil
 }
 
 type SubscriptionsSet struct {
@@ -112,62 +174,23 @@ type SubscriptionsSet struct {
 	Subscriptions []string `json:"subscriptions"`
 }
 
-func (c *Collection[T]) authSubscribeStream(data []byte, targets []string) (err error) {
-	var s SubscriptionsSet
-	if err = json.Unmarshal(data, &s); err != nil {
-		return
+// authSubscribeStream sets the topics of the realtime client,
+// replacing all the previously subscribed ones.
+func (c *Client) authSubscribeStream(ctx context.Context, clientID string, topics []string) error {
+	s := SubscriptionsSet{
+		ClientID:      clientID,
+		Subscriptions: topics,
 	}
-	s.Subscriptions = targets
-	resp, err := c.client.R().SetBody(s).Post(c.url + "/api/realtime")
+	resp, err := c.client.R().SetContext(ctx).SetBody(s).Post(c.url + "/api/realtime")
 	if err != nil {
-		return
+		return err
 	}
 	if code := resp.StatusCode(); code != http.StatusNoContent {
 		return fmt.Errorf("auth subscribe stream failed. resp status code is %v", code)
 	}
-	return
-}
-
-type Stream[T any] struct {
-	channel     *multicast.Channel[Event[T]]
-	unsubscribe func()
-
-	ready       *sync.RWMutex
-	onceCleanup *sync.Once
-}
-
-func newStream[T any]() *Stream[T] {
-	return &Stream[T]{
-		channel:     multicast.New[Event[T]](),
-		ready:       &sync.RWMutex{},
-		onceCleanup: &sync.Once{},
-	}
-}
-
-func (s *Stream[T]) Events() <-chan Event[T] {
-	return s.channel.Listen().C
-}
-
-func (s *Stream[T]) Unsubscribe() {
-	s.onceCleanup.Do(func() {
-		s.unsubscribe()
-		s.channel.Close()
-	})
-}
-
-// Deprecated: use <-stream.Ready() instead of
-func (s *Stream[T]) WaitAuthReady() error {
-	s.ready.RLock()
-	defer s.ready.RUnlock()
 	return nil
 }
 
-func (s *Stream[T]) Ready() <-chan struct{} {
-	readyCh := make(chan struct{})
-	go func() {
-		s.ready.RLock()
-		defer s.ready.RUnlock()
-		close(readyCh)
-	}()
-	return readyCh
+func decodeEventData(ev eventsource.Event, v any) error {
+	return json.Unmarshal([]byte(ev.Data()), v)
 }
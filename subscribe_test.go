@@ -1,13 +1,20 @@
 package pocketbase
 
 import (
+	"errors"
 	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-resty/resty/v2"
 	"github.com/pluja/pocketbase/migrations"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCollection_Subscribe(t *testing.T) {
@@ -159,3 +166,312 @@ func TestCollection_RealtimeReconnect(t *testing.T) {
 	}
 	assert.Equal(t, true, got)
 }
+
+func TestRealtime_SharedConnection(t *testing.T) {
+	client := NewClient(defaultURL)
+	var connects atomic.Int32
+	client.client.OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL, "/api/realtime") {
+			connects.Add(1)
+		}
+		return nil
+	})
+
+	posts := CollectionSet[map[string]any](client, migrations.PostsPublic)
+	files := CollectionSet[map[string]any](client, migrations.PostsFiles)
+
+	postsStream, err := posts.Subscribe()
+	require.NoError(t, err)
+	defer postsStream.Unsubscribe()
+	filesStream, err := files.Subscribe()
+	require.NoError(t, err)
+	defer filesStream.Unsubscribe()
+
+	assert.Equal(t, int32(1), connects.Load())
+	postsCh, filesCh := postsStream.Events(), filesStream.Events()
+
+	post, err := posts.Create(map[string]any{"field": "shared_" + time.Now().Format(time.StampMilli)})
+	require.NoError(t, err)
+	defer func() { _ = posts.Delete(post.ID) }()
+	file, err := files.Create(map[string]any{"field": "shared_" + time.Now().Format(time.StampMilli)})
+	require.NoError(t, err)
+	defer func() { _ = files.Delete(file.ID) }()
+
+	e := <-postsCh
+	assert.Equal(t, post.ID, e.Record["id"])
+	e = <-filesCh
+	assert.Equal(t, file.ID, e.Record["id"])
+
+	// the connection is kept for the remaining stream
+	filesStream.Unsubscribe()
+	_, ok := <-filesCh
+	assert.False(t, ok)
+
+	require.NoError(t, posts.Update(post.ID, map[string]any{"field": "updated"}))
+	e = <-postsCh
+	assert.Equal(t, "update", e.Action)
+	assert.Equal(t, int32(1), connects.Load())
+}
+
+func TestCollection_SubscribeOverflow(t *testing.T) {
+	client := NewClient(defaultURL)
+	collection := CollectionSet[map[string]any](client, migrations.PostsPublic)
+
+	stream, err := collection.SubscribeWith(SubscribeOptions{BufferSize: 1, OverflowPolicy: OverflowClose})
+	require.NoError(t, err)
+	defer stream.Unsubscribe()
+
+	// nobody reads the events
+	for i := 0; i < 5; i++ {
+		r, err := collection.Create(map[string]any{"field": "overflow_" + time.Now().Format(time.StampMilli)})
+		require.NoError(t, err)
+		defer func() { _ = collection.Delete(r.ID) }()
+	}
+
+	assert.Eventually(t, func() bool {
+		return errors.Is(stream.Err(), ErrStreamOverflow)
+	}, 5*time.Second, 10*time.Millisecond)
+	_, ok := <-stream.Events()
+	assert.False(t, ok)
+}
+
+func TestCollection_SubscribeSlowConsumer(t *testing.T) {
+	client := NewClient(defaultURL)
+	collection := CollectionSet[map[string]any](client, migrations.PostsPublic)
+
+	// nobody reads the events of the slow stream, its buffer overflows with the default policy
+	slow, err := collection.SubscribeWith(SubscribeOptions{BufferSize: 1})
+	require.NoError(t, err)
+	defer slow.Unsubscribe()
+	stream, err := collection.Subscribe()
+	require.NoError(t, err)
+	defer stream.Unsubscribe()
+	ch := stream.Events()
+
+	for i := 0; i < 5; i++ {
+		r, err := collection.Create(map[string]any{"field": "slow_" + time.Now().Format(time.StampMilli)})
+		require.NoError(t, err)
+		defer func() { _ = collection.Delete(r.ID) }()
+
+		select {
+		case e := <-ch:
+			assert.Equal(t, r.ID, e.Record["id"])
+		case <-time.After(5 * time.Second):
+			t.Fatal("the slow stream blocks the other streams")
+		}
+	}
+	assert.NoError(t, slow.Err())
+}
+
+func TestSubscribeOptions_Topic(t *testing.T) {
+	assert.Equal(t, "posts/*", SubscribeOptions{}.topic("posts/*"))
+
+	topic := SubscribeOptions{
+		Filter:  "status = 'published'",
+		Expand:  "author",
+		Fields:  "id,title",
+		Query:   map[string]string{"lang": "en"},
+		Headers: map[string]string{"X-Token": "abc"},
+	}.topic("posts/*")
+	u, err := url.Parse(topic)
+	require.NoError(t, err)
+	assert.Equal(t, "posts/*", u.Path)
+	assert.JSONEq(t, `{
+		"query": {"filter": "status = 'published'", "expand": "author", "fields": "id,title", "lang": "en"},
+		"headers": {"X-Token": "abc"}
+	}`, u.Query().Get("options"))
+
+	// already serialized options are kept
+	assert.Equal(t, topic, SubscribeOptions{Filter: "a = 1"}.topic(topic))
+}
+
+func TestCollection_SubscribeWithFilter(t *testing.T) {
+	client := NewClient(defaultURL)
+	collection := CollectionSet[map[string]any](client, migrations.PostsPublic)
+	field := "filtered_" + time.Now().Format(time.StampMilli)
+
+	stream, err := collection.SubscribeWith(SubscribeOptions{
+		Filter: "field = '" + field + "'",
+		Fields: "id,field",
+	}, migrations.PostsPublic+"/*")
+	require.NoError(t, err)
+	defer stream.Unsubscribe()
+	ch := stream.Events()
+
+	other, err := collection.Create(map[string]any{"field": "other_" + field})
+	require.NoError(t, err)
+	defer func() { _ = collection.Delete(other.ID) }()
+	matching, err := collection.Create(map[string]any{"field": field})
+	require.NoError(t, err)
+	defer func() { _ = collection.Delete(matching.ID) }()
+
+	e := <-ch
+	require.NoError(t, e.Error)
+	assert.Equal(t, "create", e.Action)
+	assert.Equal(t, map[string]any{"id": matching.ID, "field": field}, e.Record)
+}
+
+func TestCollection_SubscribeStates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping realtime reconnect in short mode")
+		return
+	}
+
+	client := NewClient(defaultURL)
+	var dials atomic.Int32
+	transport := &http.Transport{
+		Dial: func(network, addr string) (net.Conn, error) {
+			conn, err := net.Dial(network, addr)
+			if err == nil && dials.Add(1) == 1 {
+				// drop the first realtime connection only
+				time.AfterFunc(time.Second, func() { conn.Close() })
+			}
+			return conn, err
+		},
+	}
+	client.client.SetTransport(transport)
+	collection := CollectionSet[map[string]any](client, migrations.PostsPublic)
+
+	stream, err := collection.Subscribe()
+	require.NoError(t, err)
+	ch := stream.Events()
+	states := stream.States()
+
+	next := func() StreamState {
+		select {
+		case s := <-states:
+			return s
+		case <-time.After(5 * time.Second):
+			t.Fatal("no state change")
+			return StreamState{}
+		}
+	}
+
+	assert.Equal(t, StreamConnecting, next().Status)
+	connected := next()
+	assert.Equal(t, StreamConnected, connected.Status)
+	assert.NotEmpty(t, connected.ClientID)
+	assert.Equal(t, connected, stream.State())
+
+	reconnecting := next()
+	assert.Equal(t, StreamReconnecting, reconnecting.Status)
+	assert.Equal(t, 1, reconnecting.Attempt)
+	assert.Error(t, reconnecting.Err)
+
+	reconnected := next()
+	assert.Equal(t, StreamConnected, reconnected.Status)
+	assert.NotEqual(t, connected.ClientID, reconnected.ClientID)
+
+	// the topics are subscribed with the new client ID
+	r, err := collection.Create(map[string]any{"field": "states_" + time.Now().Format(time.StampMilli)})
+	require.NoError(t, err)
+	defer func() { _ = collection.Delete(r.ID) }()
+	select {
+	case e := <-ch:
+		assert.Equal(t, r.ID, e.Record["id"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not received after reconnect")
+	}
+
+	stream.Unsubscribe()
+	for s := range states {
+		if s.Status == StreamClosed {
+			assert.NoError(t, s.Err)
+		}
+	}
+	assert.Equal(t, StreamClosed, stream.State().Status)
+	assert.NoError(t, stream.Err())
+}
+
+func TestCollection_SubscribeRecoverGaps(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping realtime reconnect in short mode")
+		return
+	}
+
+	client := NewClient(defaultURL)
+	var dials atomic.Int32
+	var blockedUntil atomic.Int64
+	transport := &http.Transport{
+		Dial: func(network, addr string) (net.Conn, error) {
+			if time.Now().UnixNano() < blockedUntil.Load() {
+				return nil, errors.New("network is down")
+			}
+			conn, err := net.Dial(network, addr)
+			if err == nil && dials.Add(1) == 1 {
+				// the first connection is the realtime one
+				time.AfterFunc(2*time.Second, func() {
+					blockedUntil.Store(time.Now().Add(time.Second).UnixNano())
+					conn.Close()
+				})
+			}
+			return conn, err
+		},
+	}
+	client.client.SetTransport(transport).SetRetryCount(0)
+
+	writer := CollectionSet[map[string]any](NewClient(defaultURL), migrations.PostsTimestamps)
+	collection := CollectionSet[map[string]any](client, migrations.PostsTimestamps)
+	stream, err := collection.SubscribeWith(SubscribeOptions{
+		ReconnectStrategy: backoff.NewConstantBackOff(200 * time.Millisecond),
+		RecoverGaps:       true,
+	})
+	require.NoError(t, err)
+	defer stream.Unsubscribe()
+	ch := stream.Events()
+	states := stream.States()
+
+	waitState := func(status StreamStatus) {
+		timeout := time.After(10 * time.Second)
+		for {
+			select {
+			case s := <-states:
+				if s.Status == status {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("no %s state", status)
+			}
+		}
+	}
+	waitState(StreamConnected)
+
+	a, err := writer.Create(map[string]any{"field": "a"})
+	require.NoError(t, err)
+	defer func() { _ = writer.Delete(a.ID) }()
+	e := <-ch
+	assert.Equal(t, "create", e.Action)
+	assert.Equal(t, a.ID, e.Record["id"])
+
+	// changes while the connection is down
+	waitState(StreamReconnecting)
+	require.NoError(t, writer.Update(a.ID, map[string]any{"field": "a_updated"}))
+	b, err := writer.Create(map[string]any{"field": "b"})
+	require.NoError(t, err)
+	defer func() { _ = writer.Delete(b.ID) }()
+	waitState(StreamConnected)
+
+	e = <-ch
+	require.NoError(t, e.Error)
+	assert.Equal(t, "update", e.Action)
+	assert.Equal(t, "a_updated", e.Record["field"])
+	e = <-ch
+	require.NoError(t, e.Error)
+	assert.Equal(t, "create", e.Action)
+	assert.Equal(t, b.ID, e.Record["id"])
+
+	// live events are resumed
+	c, err := writer.Create(map[string]any{"field": "c"})
+	require.NoError(t, err)
+	defer func() { _ = writer.Delete(c.ID) }()
+	e = <-ch
+	assert.Equal(t, "create", e.Action)
+	assert.Equal(t, c.ID, e.Record["id"])
+}
+
+func TestTargetsFilter(t *testing.T) {
+	assert.Equal(t, "", targetsFilter([]string{"posts"}).String())
+	assert.Equal(t, "", targetsFilter([]string{"posts/abc", "posts/*"}).String())
+	assert.Equal(t, "id = 'abc'", targetsFilter([]string{"posts/abc"}).String())
+	assert.Equal(t, "(id = 'abc' || id = 'def')", targetsFilter([]string{"posts/abc", "posts/def?options={}"}).String())
+}
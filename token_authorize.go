@@ -1,6 +1,8 @@
 package pocketbase
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,59 +11,86 @@ import (
 )
 
 type authorizeToken struct {
+	authToken
 	client      *resty.Client
-	url         string
-	token       string
-	tokenValid  time.Time
+	url         string // auth collection URL
 	tokenSingle singleflight.Group
 }
 
-func newAuthorizeToken(c *resty.Client, url string, token string) authStore {
-	c.SetHeader("Authorization", token)
-	return &authorizeToken{
+func newAuthorizeToken(c *resty.Client, url string, token string) *authorizeToken {
+	a := &authorizeToken{
 		client:      c,
 		url:         url,
-		token:       token,
 		tokenSingle: singleflight.Group{},
 	}
+	a.token = token // refreshed on the first authorization
+	return a
 }
 
-func (a *authorizeToken) authorize() error {
-	type authResponse struct {
-		Token string `json:"token"`
-	}
-	_, err, _ := a.tokenSingle.Do("auth-refresh", func() (interface{}, error) {
-		if time.Now().Before(a.tokenValid) {
-			return nil, nil
-		}
-		resp, err := a.client.R().
-			SetHeader("Content-Type", "application/json").
-			SetHeader("Authorization", a.token).
-			SetResult(&authResponse{}).
-			Post(a.url)
-		if err != nil {
-			return nil, fmt.Errorf("[auth-refresh] can't send request to pocketbase %w", err)
+func (a *authorizeToken) authorize(ctx context.Context) error {
+	return singleflightCtx(ctx, &a.tokenSingle, "auth-refresh", func(ctx context.Context) error {
+		token, valid := a.get()
+		if time.Now().Before(valid) {
+			return nil
 		}
-		if resp.IsError() {
-			return nil, fmt.Errorf("[auth-refresh] pocketbase returned status: %d, msg: %s, err %w",
-				resp.StatusCode(),
-				resp.String(),
-				ErrInvalidResponse,
-			)
+		return a.refresh(ctx, token)
+	})
+}
+
+func (a *authorizeToken) renew(ctx context.Context, token string) error {
+	return singleflightCtx(ctx, &a.tokenSingle, "auth-refresh", func(ctx context.Context) error {
+		current, _ := a.get()
+		if current != token {
+			return nil
 		}
-		auth := *resp.Result().(*authResponse)
-		a.token = auth.Token
-		a.client.SetHeader("Authorization", auth.Token)
-		a.tokenValid = time.Now().Add(60 * time.Minute)
-		return nil, nil
+		return a.refresh(ctx, current)
 	})
-	return err
 }
 
-func (a *authorizeToken) IsValid() bool {
-	return time.Now().Before(a.tokenValid)
+func (a *authorizeToken) refresh(ctx context.Context, token string) error {
+	auth, err := refreshToken(ctx, a.client, a.url, token)
+	if err != nil {
+		return err
+	}
+	return a.update(auth)
+}
+
+// authorizeStaticToken uses a non-refreshable token (e.g. of the impersonation) until its expiry.
+type authorizeStaticToken struct {
+	authToken
 }
 
-func (a *authorizeToken) Token() string {
-	return a.token
+func newAuthorizeStaticToken(token string) *authorizeStaticToken {
+	a := &authorizeStaticToken{}
+	a.token = token
+	a.tokenValid = tokenExpiry(token)
+	return a
+}
+
+func (a *authorizeStaticToken) authorize(_ context.Context) error {
+	if _, valid := a.get(); !valid.IsZero() && time.Now().After(valid) {
+		return errors.New("[auth] the token expired and it can't be refreshed")
+	}
+	return nil
+}
+
+func (a *authorizeStaticToken) renew(_ context.Context, _ string) error {
+	return nil
+}
+
+// refreshToken exchanges the token for a new one in the auth collection at url.
+func refreshToken(ctx context.Context, client *resty.Client, url string, token string) (authResponse, error) {
+	resp, err := client.R().
+		SetContext(withoutAuthRetry(ctx)).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Authorization", token).
+		SetResult(&authResponse{}).
+		Post(url + "/auth-refresh")
+	if err != nil {
+		return authResponse{}, fmt.Errorf("[auth-refresh] can't send request to pocketbase %w", err)
+	}
+	if resp.IsError() {
+		return authResponse{}, fmt.Errorf("[auth-refresh] %w", newApiError(resp))
+	}
+	return *resp.Result().(*authResponse), nil
 }
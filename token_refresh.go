@@ -0,0 +1,154 @@
+package pocketbase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	// defaultTokenLifetime is assumed for the tokens without the exp claim.
+	defaultTokenLifetime = 60 * time.Minute
+	// maxRefreshMargin is the longest time a token is refreshed before its expiry.
+	maxRefreshMargin = time.Minute
+	// backgroundRefreshRetry is the delay of the next background refresh after a failed one.
+	backgroundRefreshRetry = 10 * time.Second
+)
+
+// tokenExpiry returns the exp claim of the JWT, it's zero if the token can't be decoded.
+// The signature isn't verified, PocketBase does it on every request anyway.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(claims.Exp), 0)
+}
+
+// tokenRefreshAt returns when the token should be refreshed: before its expiry
+// by a tenth of the remaining lifetime, but at most by maxRefreshMargin.
+func tokenRefreshAt(token string, now time.Time) time.Time {
+	exp := tokenExpiry(token)
+	if exp.IsZero() {
+		return now.Add(defaultTokenLifetime)
+	}
+	return exp.Add(-refreshMargin(exp.Sub(now)))
+}
+
+func refreshMargin(lifetime time.Duration) time.Duration {
+	return max(0, min(lifetime/10, maxRefreshMargin))
+}
+
+// WithBackgroundRefresh refreshes the token in the background until the context is done,
+// so the requests don't wait for it. Every refresh happens at a random moment shortly
+// before the token should be refreshed, so the clients sharing a token don't refresh it at once.
+func WithBackgroundRefresh(ctx context.Context) ClientOption {
+	return func(c *Client) {
+		c.refreshCtx = ctx
+	}
+}
+
+func (c *Client) refreshInBackground(ctx context.Context) {
+	timer := time.NewTimer(backgroundRefreshDelay(c.auth().validUntil()))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		delay := backgroundRefreshRetry
+		a := c.auth()
+		if err := a.renew(ctx, a.Token()); err != nil {
+			if c.restDebug {
+				log.Printf("background token refresh failed: %v", err)
+			}
+		} else if next := backgroundRefreshDelay(c.auth().validUntil()); next > 0 {
+			delay = next
+		}
+		timer.Reset(delay)
+	}
+}
+
+// backgroundRefreshDelay returns the delay of the next background refresh,
+// jittered by up to a tenth of the remaining time (at most maxRefreshMargin).
+func backgroundRefreshDelay(validUntil time.Time) time.Duration {
+	remaining := time.Until(validUntil)
+	if validUntil.IsZero() || remaining <= 0 {
+		return 0
+	}
+	if margin := refreshMargin(remaining); margin > 0 {
+		remaining -= rand.N(margin)
+	}
+	return remaining
+}
+
+type authRetryKey struct{}
+
+// withoutAuthRetry marks the requests that aren't retried after a 401 response,
+// i.e. the auth requests themselves and the already retried ones.
+func withoutAuthRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, authRetryKey{}, true)
+}
+
+// retryUnauthorized renews the token and sends the request once again after a 401 response,
+// e.g. when the token was revoked or it expired before the refresh.
+// Only the requests with the authorizer's token are retried. The token of a request
+// with a drained body (e.g. an uploaded file) is renewed too, but its 401 response is kept.
+func (c *Client) retryUnauthorized(_ *resty.Client, resp *resty.Response) error {
+	r := resp.Request
+	token := r.Header.Get("Authorization")
+	if resp.StatusCode() != http.StatusUnauthorized || token == "" ||
+		r.Context().Value(authRetryKey{}) != nil || token != c.auth().Token() {
+		return nil
+	}
+
+	ctx := withoutAuthRetry(r.Context())
+	a := c.auth()
+	if err := a.renew(ctx, token); err != nil || a.Token() == token || !rewindable(r) {
+		return nil // keep the original response
+	}
+
+	if body := resp.RawBody(); body != nil {
+		body.Close()
+	}
+	r.SetContext(ctx)
+	r.Header.Set("Authorization", a.Token())
+	r.QueryParam = url.Values{} // already in r.URL
+	retried, err := r.Execute(r.Method, r.URL)
+	if err != nil {
+		return err
+	}
+	*resp = *retried
+	return nil
+}
+
+// rewindable reports whether the request body can be sent again. The readers
+// (e.g. the multipart files) are drained by the first request, the other bodies are encoded again.
+func rewindable(r *resty.Request) bool {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		return false
+	}
+	_, isReader := r.Body.(io.Reader)
+	return !isReader
+}
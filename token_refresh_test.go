@@ -0,0 +1,149 @@
+package pocketbase
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/pluja/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testJWT(payload string) string {
+	return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".signature"
+}
+
+func TestTokenRefreshAt(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name  string
+		token string
+		want  time.Time
+	}{
+		{"long lifetime", testJWT(fmt.Sprintf(`{"exp":%d}`, now.Add(time.Hour).Unix())), now.Add(time.Hour - time.Minute)},
+		{"short lifetime", testJWT(fmt.Sprintf(`{"exp":%d}`, now.Add(100*time.Second).Unix())), now.Add(90 * time.Second)},
+		{"expired", testJWT(fmt.Sprintf(`{"exp":%d}`, now.Add(-time.Second).Unix())), now.Add(-time.Second)},
+		{"no exp claim", testJWT(`{"id":"abc"}`), now.Add(defaultTokenLifetime)},
+		{"not a JWT", "invalid_token", now.Add(defaultTokenLifetime)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tokenRefreshAt(tt.token, now))
+		})
+	}
+}
+
+func TestBackgroundRefreshDelay(t *testing.T) {
+	assert.Zero(t, backgroundRefreshDelay(time.Time{}))
+	assert.Zero(t, backgroundRefreshDelay(time.Now().Add(-time.Second)))
+	for i := 0; i < 10; i++ {
+		delay := backgroundRefreshDelay(time.Now().Add(time.Hour))
+		assert.LessOrEqual(t, delay, time.Hour)
+		assert.Greater(t, delay, time.Hour-time.Minute-time.Second)
+	}
+}
+
+func TestClient_RetryUnauthorized(t *testing.T) {
+	client := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	var requests atomic.Int32
+	client.client.OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
+		if strings.Contains(r.URL, "/api/collections") && !strings.Contains(r.URL, "/auth-") {
+			requests.Add(1)
+		}
+		return nil
+	})
+	require.NoError(t, client.Authorize())
+
+	// the token is revoked on the server side
+	a := client.authorizer.(*authorizeEmailPassword)
+	a.set("invalid_token")
+
+	_, err := client.Collections().List(ParamsList{Filters: "name = 'users'"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.NotEqual(t, "invalid_token", client.AuthStore().Token())
+
+	// the anonymous requests aren't retried
+	_, err = NewClient(defaultURL).Collections().List(ParamsList{})
+	assert.Error(t, err)
+}
+
+func TestClient_RetryUnauthorizedUpload(t *testing.T) {
+	client := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	var uploads atomic.Int32
+	client.client.OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
+		if strings.HasSuffix(r.URL, "/api/backups/upload") {
+			uploads.Add(1)
+		}
+		return nil
+	})
+	require.NoError(t, client.Authorize())
+
+	// the token expired on the server side
+	a := client.authorizer.(*authorizeEmailPassword)
+	a.set("invalid_token")
+
+	backupName := "retry_upload_test.zip"
+	upload := func() error {
+		file, err := os.Open("./testressources/pb_backup.zip")
+		require.NoError(t, err)
+		defer file.Close()
+		return client.Backup().Upload(backupName, file)
+	}
+
+	// the drained file isn't sent again, but the token is renewed
+	err := upload()
+	var apiErr *ApiError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.Status)
+	assert.Equal(t, int32(1), uploads.Load())
+	assert.NotEqual(t, "invalid_token", client.AuthStore().Token())
+
+	require.NoError(t, upload())
+	assert.Equal(t, int32(2), uploads.Load())
+	require.NoError(t, client.Backup().Delete(backupName))
+}
+
+func TestClient_RefreshConcurrentRequests(t *testing.T) {
+	client := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	require.NoError(t, client.Authorize())
+	token := client.AuthStore().Token()
+
+	// the requests don't race with the token updates of the refreshes (go test -race)
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				_, err := client.Collections().List(ParamsList{})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		require.NoError(t, client.authChanged(authResponse{Token: token}))
+	}
+	wg.Wait()
+}
+
+func TestClient_WithBackgroundRefresh(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := NewClient(defaultURL,
+		WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword),
+		WithBackgroundRefresh(ctx),
+	)
+	assert.Eventually(t, client.AuthStore().IsValid, 5*time.Second, 10*time.Millisecond)
+	assert.WithinDuration(t, tokenExpiry(client.AuthStore().Token()), client.authorizer.validUntil(), time.Minute)
+}
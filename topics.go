@@ -0,0 +1,94 @@
+package pocketbase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/donovanhide/eventsource"
+)
+
+type (
+	// Realtime is the service for the custom realtime topics,
+	// e.g. the messages sent by the app hooks with subscriptions.Message.
+	Realtime struct {
+		*Client
+	}
+
+	// Message is a raw event of a custom topic.
+	Message struct {
+		// Topic is the eventsource event name, i.e. the subscribed topic with its options.
+		Topic string
+		Data  json.RawMessage
+	}
+
+	// MessageStream is a realtime stream of the custom topic messages.
+	MessageStream struct {
+		*eventStream[Message]
+	}
+)
+
+// Realtime returns the service for the custom realtime topics.
+func (c *Client) Realtime() Realtime {
+	return Realtime{
+		Client: c,
+	}
+}
+
+// Decode unmarshals the JSON payload of the message into v.
+func (m Message) Decode(v any) error {
+	if err := json.Unmarshal(m.Data, v); err != nil {
+		return fmt.Errorf("can't unmarshal message %s, err %w", m.Topic, err)
+	}
+	return nil
+}
+
+// DecodeMessage returns the JSON payload of the message as T.
+func DecodeMessage[T any](m Message) (T, error) {
+	var v T
+	err := m.Decode(&v)
+	return v, err
+}
+
+func (r Realtime) Subscribe(topics ...string) (*MessageStream, error) {
+	return r.SubscribeCtx(context.Background(), topics...)
+}
+
+// SubscribeCtx is the same as Subscribe, but the stream is closed
+// as soon as the provided context is done.
+func (r Realtime) SubscribeCtx(ctx context.Context, topics ...string) (*MessageStream, error) {
+	return r.SubscribeWithCtx(ctx, SubscribeOptions{}, topics...)
+}
+
+func (r Realtime) SubscribeWith(opts SubscribeOptions, topics ...string) (*MessageStream, error) {
+	return r.SubscribeWithCtx(context.Background(), opts, topics...)
+}
+
+// SubscribeWithCtx subscribes to the custom topics, the messages are delivered
+// as they were sent, without any decoding. The options are applied the same as for
+// the record subscriptions, except RecoverGaps which is ignored. The custom topics
+// can't be polled, an error is returned if Polling, PollInterval or PollDeletesInterval is set.
+//
+// The stream shares the realtime connection with all the other streams of the client.
+func (r Realtime) SubscribeWithCtx(ctx context.Context, opts SubscribeOptions, topics ...string) (*MessageStream, error) {
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("[realtime] at least one topic is required")
+	}
+	if opts.Polling != PollingDisabled || opts.PollInterval != 0 || opts.PollDeletesInterval != 0 {
+		return nil, fmt.Errorf("[realtime] the custom topics can't be polled")
+	}
+	if err := r.AuthorizeCtx(ctx); err != nil {
+		return nil, err
+	}
+
+	stream, err := openStream(ctx, r.Client, opts, topics, func(ev eventsource.Event) Message {
+		return Message{
+			Topic: ev.Event(),
+			Data:  json.RawMessage(ev.Data()),
+		}
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MessageStream{stream}, nil
+}
@@ -0,0 +1,59 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pluja/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRealtime_Subscribe(t *testing.T) {
+	client := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	topic := "custom_" + time.Now().Format("150405.000")
+
+	_, err := client.Realtime().Subscribe()
+	assert.Error(t, err)
+	_, err = client.Realtime().SubscribeWith(SubscribeOptions{Polling: PollingFallback}, topic)
+	assert.ErrorContains(t, err, "can't be polled")
+	_, err = client.Realtime().SubscribeWith(SubscribeOptions{PollInterval: time.Second}, topic)
+	assert.ErrorContains(t, err, "can't be polled")
+
+	stream, err := client.Realtime().Subscribe(topic)
+	require.NoError(t, err)
+	defer stream.Unsubscribe()
+	ch := stream.Events()
+
+	type payload struct {
+		Text  string `json:"text"`
+		Count int    `json:"count"`
+	}
+	resp, err := client.client.R().
+		SetBody(map[string]any{"topic": topic, "data": payload{Text: "hello", Count: 2}}).
+		Post(client.url + "/api/test/broadcast")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode(), resp.String())
+
+	select {
+	case m := <-ch:
+		assert.Equal(t, topic, m.Topic)
+		got, err := DecodeMessage[payload](m)
+		require.NoError(t, err)
+		assert.Equal(t, payload{Text: "hello", Count: 2}, got)
+
+		_, err = DecodeMessage[[]string](m)
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not received")
+	}
+}
+
+func TestMessage_Decode(t *testing.T) {
+	m := Message{Topic: "topic", Data: json.RawMessage(`{"a":1}`)}
+	var v map[string]int
+	require.NoError(t, m.Decode(&v))
+	assert.Equal(t, map[string]int{"a": 1}, v)
+}